@@ -0,0 +1,109 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 7:20 下午
+// version: 1.0.0
+// desc   : Accept 请求头解析
+
+package codec
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MediaRange Accept 请求头中的一项
+type MediaRange struct {
+	Type    string  // 主类型，可为 *
+	SubType string  // 子类型，可为 *
+	Q       float64 // 权重，0 表示不接受
+}
+
+// ParseAccept 解析 Accept 请求头
+//
+// 为空时视为 */*，无法解析的项直接忽略
+func ParseAccept(accept string) []*MediaRange {
+	if strings.TrimSpace(accept) == "" {
+		accept = "*/*"
+	}
+
+	ranges := make([]*MediaRange, 0)
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		slash := strings.IndexByte(mediaType, '/')
+		if slash < 1 || slash == len(mediaType)-1 {
+			continue
+		}
+
+		mr := &MediaRange{Type: mediaType[:slash], SubType: mediaType[slash+1:], Q: 1}
+		for _, param := range params[1:] {
+			kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+			if len(kv) == 2 && strings.ToLower(kv[0]) == "q" {
+				if q, err := strconv.ParseFloat(kv[1], 64); err == nil && q >= 0 && q <= 1 {
+					mr.Q = q
+				}
+			}
+		}
+		ranges = append(ranges, mr)
+	}
+	return ranges
+}
+
+// Matches 是否匹配媒体类型
+func (mr *MediaRange) Matches(mediaType string) bool {
+	slash := strings.IndexByte(mediaType, '/')
+	if slash < 0 {
+		return false
+	}
+	return (mr.Type == "*" || mr.Type == mediaType[:slash]) && (mr.SubType == "*" || mr.SubType == mediaType[slash+1:])
+}
+
+// specificity 具体程度，越具体越优先
+func (mr *MediaRange) specificity() int {
+	switch {
+	case mr.Type == "*":
+		return 0
+	case mr.SubType == "*":
+		return 1
+	}
+	return 2
+}
+
+// match 获取媒体类型的权重，以及匹配项在 Accept 中的位置
+//
+// 多项都匹配时，取最具体的一项
+func match(ranges []*MediaRange, mediaType string) (float64, int) {
+	var (
+		matched *MediaRange
+		index   int
+	)
+	for i, mr := range ranges {
+		if mr.Matches(mediaType) && (matched == nil || mr.specificity() > matched.specificity()) {
+			matched, index = mr, i
+		}
+	}
+	if matched == nil {
+		return 0, 0
+	}
+	return matched.Q, index
+}
+
+// Accepts Accept 请求头是否接受该媒体类型
+func Accepts(accept, mediaType string) bool {
+	q, _ := match(ParseAccept(accept), Normalize(mediaType))
+	return q > 0
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 7:05 下午
+// version: 1.0.0
+// desc   : 编解码器
+//			按媒体类型注册编码器，并根据 Accept 请求头协商响应格式
+
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNotAcceptable 没有满足 Accept 请求头的编码器
+	ErrNotAcceptable = errors.New("not acceptable")
+
+	mu       sync.RWMutex
+	encoders = make(map[string]Encoder) // 媒体类型 -> 编码器
+	ordered  = make([]string, 0)        // 按注册顺序排列的媒体类型，协商时靠前的优先
+)
+
+// Encoder 响应编码器
+type Encoder interface {
+	// ContentType 响应的 Content-Type，如 application/json;charset=utf-8
+	ContentType() string
+
+	// Encode 编码响应结果
+	Encode(writer io.Writer, value interface{}) error
+}
+
+// funcEncoder 函数式编码器
+type funcEncoder struct {
+	contentType string
+	encode      func(value interface{}) ([]byte, error)
+}
+
+// NewEncoder 用编码函数创建编码器
+func NewEncoder(contentType string, encode func(value interface{}) ([]byte, error)) Encoder {
+	return &funcEncoder{
+		contentType: contentType,
+		encode:      encode,
+	}
+}
+
+// ContentType 响应的 Content-Type
+func (fe *funcEncoder) ContentType() string {
+	return fe.contentType
+}
+
+// Encode 编码响应结果
+func (fe *funcEncoder) Encode(writer io.Writer, value interface{}) error {
+	bs, err := fe.encode(value)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(bs)
+	return err
+}
+
+// Register 注册编码器
+//
+// 同一媒体类型重复注册时覆盖，但保留原来的协商顺序
+func Register(mediaType string, encoder Encoder) {
+	mediaType = Normalize(mediaType)
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := encoders[mediaType]; !ok {
+		ordered = append(ordered, mediaType)
+	}
+	encoders[mediaType] = encoder
+}
+
+// GetEncoder 获取媒体类型对应的编码器
+func GetEncoder(mediaType string) Encoder {
+	mu.RLock()
+	defer mu.RUnlock()
+	return encoders[Normalize(mediaType)]
+}
+
+// MediaTypes 所有已注册编码器的媒体类型
+func MediaTypes() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]string(nil), ordered...)
+}
+
+// Negotiate 按 Accept 请求头选择编码器
+//
+// 取 Acceptable 排在第一位的媒体类型
+// 没有可用的编码器时返回 ErrNotAcceptable
+func Negotiate(accept string, produces ...string) (string, Encoder, error) {
+	mediaTypes := Acceptable(accept, produces...)
+	if len(mediaTypes) == 0 {
+		candidates := produces
+		if len(candidates) == 0 {
+			candidates = MediaTypes()
+		}
+		return "", nil, fmt.Errorf("%w: the accepted media types [%v] are not supported, available are %v", ErrNotAcceptable, accept, candidates)
+	}
+	return mediaTypes[0], GetEncoder(mediaTypes[0]), nil
+}
+
+// Acceptable 按 Accept 请求头排列所有可用的媒体类型，靠前的优先
+//
+// produces 为路由可响应的媒体类型，为空时可响应所有已注册的媒体类型
+// q 值最高的优先，相同时按 Accept 中的顺序，再按 produces 或注册的顺序
+// Accept 中最想要的类型都无法响应，又带有 */* 时（如浏览器的 Accept），直接按 produces 或注册的顺序，即 json 优先于 xml
+func Acceptable(accept string, produces ...string) []string {
+	candidates := produces
+	if len(candidates) == 0 {
+		candidates = MediaTypes()
+	}
+	ranges := ParseAccept(accept)
+
+	var (
+		top      float64
+		wildcard bool
+	)
+	for _, mr := range ranges {
+		if mr.Q > top {
+			top = mr.Q
+		}
+		if mr.specificity() == 0 && mr.Q > 0 {
+			wildcard = true
+		}
+	}
+
+	type accepted struct {
+		mediaType string
+		q         float64
+		index     int
+	}
+	var (
+		list      = make([]accepted, 0, len(candidates))
+		seen      = make(map[string]bool, len(candidates))
+		preferred bool
+	)
+	for _, candidate := range candidates {
+		candidate = Normalize(candidate)
+		if seen[candidate] || GetEncoder(candidate) == nil {
+			continue
+		}
+		seen[candidate] = true
+		q, index := match(ranges, candidate)
+		if q <= 0 {
+			continue
+		}
+		if q == top && ranges[index].specificity() > 0 {
+			preferred = true
+		}
+		list = append(list, accepted{mediaType: candidate, q: q, index: index})
+	}
+
+	// 最想要的类型都无法响应且带有 */* 时，保持 produces 或注册的顺序
+	if preferred || !wildcard {
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].q != list[j].q {
+				return list[i].q > list[j].q
+			}
+			return list[i].index < list[j].index
+		})
+	}
+
+	mediaTypes := make([]string, len(list))
+	for i, item := range list {
+		mediaTypes[i] = item.mediaType
+	}
+	return mediaTypes
+}
+
+// Normalize 去除媒体类型的参数，并转为小写
+func Normalize(mediaType string) string {
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		return parsed
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 7:50 下午
+// version: 1.0.0
+// desc   : 编解码器测试
+
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := []struct {
+		accept   string
+		produces []string
+		expected string
+	}{
+		{"", nil, MediaTypeJSON},
+		{"*/*", nil, MediaTypeJSON},
+		{"application/xml", nil, MediaTypeXML},
+		{"text/plain;q=0.5, application/json;q=0.4", nil, MediaTypeText},
+		{"application/xml, application/json", nil, MediaTypeXML},
+		{"application/json;q=0, */*", []string{MediaTypeJSON, MediaTypeYAML}, MediaTypeYAML},
+		{"*/*", []string{"application/toml;charset=utf-8", MediaTypeJSON}, MediaTypeTOML},
+		{"text/html, */*;q=0.1", []string{MediaTypeXML}, MediaTypeXML},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", nil, MediaTypeJSON},
+		{"text/html, application/xml;q=0.9", nil, MediaTypeXML},
+		{"application/xml, */*;q=0.8", nil, MediaTypeXML},
+	}
+	for _, c := range cases {
+		mediaType, encoder, err := Negotiate(c.accept, c.produces...)
+		if err != nil || encoder == nil || mediaType != c.expected {
+			t.Errorf("Accept [%v] with produces %v should be negotiated as [%v], but [%v], %v", c.accept, c.produces, c.expected, mediaType, err)
+		}
+	}
+
+	if _, _, err := Negotiate("text/html", MediaTypeJSON); !errors.Is(err, ErrNotAcceptable) {
+		t.Errorf("should be not acceptable, but %v", err)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GetEncoder("text/plain; charset=utf-8").Encode(&buf, 12); err != nil || buf.String() != "12" {
+		t.Errorf("should be encoded as text, but [%v], %v", buf.String(), err)
+	}
+
+	Register("text/csv", NewEncoder("text/csv", func(value interface{}) ([]byte, error) {
+		return []byte("a,b"), nil
+	}))
+	if mediaType, _, _ := Negotiate("text/csv"); mediaType != "text/csv" {
+		t.Errorf("registered encoder should be negotiated, but [%v]", mediaType)
+	}
+}
+
+type Form struct {
+	Name  string   `form:"name"`
+	Age   int      `json:"age"`
+	Tags  []string `form:"tag"`
+	Level uint
+}
+
+func TestDecoder(t *testing.T) {
+	form := new(Form)
+	if err := GetDecoder(MediaTypeForm+"; charset=utf-8").Decode(strings.NewReader("name=gox&age=18&tag=a&tag=b&level=3"), form); err != nil {
+		t.Fatal(err)
+	}
+	expected := Form{Name: "gox", Age: 18, Tags: []string{"a", "b"}, Level: 3}
+	if !reflect.DeepEqual(*form, expected) {
+		t.Errorf("should be decoded as %+v, but %+v", expected, *form)
+	}
+	if err := GetDecoder(MediaTypeForm).Decode(strings.NewReader("age=abc"), new(Form)); err == nil {
+		t.Error("conversion error should be reported")
+	}
+
+	values := make(map[string]int)
+	if err := GetDecoder(MediaTypeForm).Decode(strings.NewReader("a=1&b=2"), &values); err != nil || values["b"] != 2 {
+		t.Errorf("should be decoded to map, but %v, %v", values, err)
+	}
+
+	problem := new(Form)
+	if err := GetDecoder("application/problem+json").Decode(strings.NewReader(`{"age":20}`), problem); err != nil || problem.Age != 20 {
+		t.Errorf("+json should be decoded as json, but %+v, %v", problem, err)
+	}
+	if GetDecoder("application/octet-stream") != nil {
+		t.Error("unknown media type should not have decoder")
+	}
+}
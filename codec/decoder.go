@@ -0,0 +1,211 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 8:30 下午
+// version: 1.0.0
+// desc   : 请求体解码器
+//			按 Content-Type 选择解码器，解析 RequestBody 参数
+
+package codec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/yhyzgn/gox/util"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	MediaTypeForm = "application/x-www-form-urlencoded" // 表单
+)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = make(map[string]Decoder) // 媒体类型 -> 解码器
+	typeValues = reflect.TypeOf(url.Values{})
+)
+
+// Decoder 请求体解码器
+type Decoder interface {
+	// Decode 将请求体解码到 value 中，value 为指针
+	Decode(reader io.Reader, value interface{}) error
+}
+
+// DecoderFunc 函数式解码器
+type DecoderFunc func(reader io.Reader, value interface{}) error
+
+// Decode 解码请求体
+func (fn DecoderFunc) Decode(reader io.Reader, value interface{}) error {
+	return fn(reader, value)
+}
+
+func init() {
+	RegisterDecoder(MediaTypeJSON, DecoderFunc(func(reader io.Reader, value interface{}) error {
+		return json.NewDecoder(reader).Decode(value)
+	}))
+	RegisterDecoder(MediaTypeXML, DecoderFunc(decodeXML))
+	RegisterDecoder("text/xml", DecoderFunc(decodeXML))
+	RegisterDecoder(MediaTypeYAML, DecoderFunc(decodeYAML))
+	RegisterDecoder(mediaTypeXYAML, DecoderFunc(decodeYAML))
+	RegisterDecoder(MediaTypeTOML, DecoderFunc(func(reader io.Reader, value interface{}) error {
+		_, err := toml.DecodeReader(reader, value)
+		return err
+	}))
+	RegisterDecoder(MediaTypeForm, DecoderFunc(decodeForm))
+}
+
+// RegisterDecoder 注册解码器，同一媒体类型重复注册时覆盖
+func RegisterDecoder(mediaType string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[Normalize(mediaType)] = decoder
+}
+
+// GetDecoder 获取媒体类型对应的解码器
+//
+// +json、+xml 结尾的媒体类型使用 json、xml 解码器，如 application/problem+json
+func GetDecoder(mediaType string) Decoder {
+	mediaType = Normalize(mediaType)
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	if decoder, ok := decoders[mediaType]; ok {
+		return decoder
+	}
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return decoders[MediaTypeJSON]
+	case strings.HasSuffix(mediaType, "+xml"):
+		return decoders[MediaTypeXML]
+	}
+	return nil
+}
+
+// decodeXML xml 解码
+func decodeXML(reader io.Reader, value interface{}) error {
+	return xml.NewDecoder(reader).Decode(value)
+}
+
+// decodeYAML yaml 解码
+func decodeYAML(reader io.Reader, value interface{}) error {
+	return yaml.NewDecoder(reader).Decode(value)
+}
+
+// decodeForm 表单解码
+//
+// 支持 url.Values、map[string]string、map[string][]string 和结构体
+// 结构体字段名依次取 form 标签、json 标签和首字母小写的字段名
+func decodeForm(reader io.Reader, value interface{}) error {
+	bs, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	values, err := url.ParseQuery(string(bs))
+	if err != nil {
+		return err
+	}
+
+	target := reflect.ValueOf(value)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("the decoding target must be pointer, but now is [%T]", value)
+	}
+	target = target.Elem()
+
+	switch {
+	case target.Type() == typeValues || target.Type() == reflect.TypeOf(map[string][]string{}):
+		target.Set(reflect.ValueOf(values).Convert(target.Type()))
+		return nil
+	case target.Kind() == reflect.Map && target.Type().Key().Kind() == reflect.String:
+		result := reflect.MakeMap(target.Type())
+		for key := range values {
+			item, err := util.ConvertValue(target.Type().Elem(), values.Get(key))
+			if err != nil {
+				return err
+			}
+			result.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), item)
+		}
+		target.Set(result)
+		return nil
+	case target.Kind() == reflect.Struct:
+		return decodeFormStruct(values, target)
+	}
+	return fmt.Errorf("the form can not be decoded to [%v]", target.Type())
+}
+
+// decodeFormStruct 将表单解码到结构体
+func decodeFormStruct(values url.Values, target reflect.Value) error {
+	tp := target.Type()
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if field.PkgPath != "" {
+			// 未导出字段
+			continue
+		}
+
+		name := util.FirstToLower(field.Name)
+		for _, tag := range []string{"form", "json"} {
+			if tn := strings.Split(field.Tag.Get(tag), ",")[0]; tn != "" {
+				name = tn
+				break
+			}
+		}
+		if name == "-" {
+			continue
+		}
+
+		items, ok := values[name]
+		if !ok || len(items) == 0 {
+			continue
+		}
+
+		fieldValue := target.Field(i)
+		if field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() != reflect.Uint8 {
+			slice := reflect.MakeSlice(field.Type, 0, len(items))
+			for _, item := range items {
+				converted, err := util.ConvertValue(field.Type.Elem(), item)
+				if err != nil {
+					return fieldError(name, err)
+				}
+				slice = reflect.Append(slice, converted)
+			}
+			fieldValue.Set(slice)
+			continue
+		}
+
+		converted, err := util.ConvertValue(field.Type, items[0])
+		if err != nil {
+			return fieldError(name, err)
+		}
+		fieldValue.Set(converted)
+	}
+	return nil
+}
+
+// fieldError 记录转换失败的字段名
+func fieldError(name string, err error) error {
+	if ce, ok := err.(*util.ConversionError); ok {
+		ce.Name = name
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 7:35 下午
+// version: 1.0.0
+// desc   : 内置编码器
+
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	MediaTypeJSON  = "application/json"   // json
+	MediaTypeXML   = "application/xml"    // xml
+	MediaTypeText  = "text/plain"         // 纯文本
+	MediaTypeYAML  = "application/yaml"   // yaml
+	MediaTypeTOML  = "application/toml"   // toml
+	MediaTypeAny   = "*/*"                // 任意类型
+	charsetUTF8    = ";charset=utf-8"     // 默认字符集
+	mediaTypeXYAML = "application/x-yaml" // yaml 的旧媒体类型
+)
+
+func init() {
+	// json 排在第一位，Accept 为 */* 时默认响应 json
+	Register(MediaTypeJSON, NewEncoder(MediaTypeJSON+charsetUTF8, json.Marshal))
+	Register(MediaTypeXML, NewEncoder(MediaTypeXML+charsetUTF8, xml.Marshal))
+	Register("text/xml", NewEncoder("text/xml"+charsetUTF8, xml.Marshal))
+	Register(MediaTypeText, NewEncoder(MediaTypeText+charsetUTF8, encodeText))
+	Register(MediaTypeYAML, NewEncoder(MediaTypeYAML+charsetUTF8, yaml.Marshal))
+	Register(mediaTypeXYAML, NewEncoder(mediaTypeXYAML+charsetUTF8, yaml.Marshal))
+	Register(MediaTypeTOML, NewEncoder(MediaTypeTOML+charsetUTF8, encodeTOML))
+}
+
+// encodeText 纯文本编码
+func encodeText(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case error:
+		return []byte(v.Error()), nil
+	}
+	return []byte(fmt.Sprint(value)), nil
+}
+
+// encodeTOML toml 编码
+func encodeTOML(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
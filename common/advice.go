@@ -0,0 +1,85 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 10:10 下午
+// version: 1.0.0
+// desc   : 异常通知
+//			按错误类型处理异常，可作用于全局、控制器或路由分组
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+var typeError = reflect.TypeOf((*error)(nil)).Elem()
+
+// ErrorHandlerFunc 错误处理器，负责响应该错误
+type ErrorHandlerFunc func(writer http.ResponseWriter, request *http.Request, err error)
+
+// ErrorAdvice 按错误类型匹配的错误处理器
+type ErrorAdvice struct {
+	target  reflect.Type     // 错误类型
+	handler ErrorHandlerFunc // 错误处理器
+}
+
+// NewErrorAdvice 创建错误处理器
+//
+// target 与 errors.As 的第二个参数相同，是指向错误类型的非空指针，如 new(*common.HTTPError)、new(net.Error)
+func NewErrorAdvice(target interface{}, handler ErrorHandlerFunc) *ErrorAdvice {
+	tp := reflect.TypeOf(target)
+	if tp == nil || tp.Kind() != reflect.Ptr || reflect.ValueOf(target).IsNil() {
+		panic(fmt.Sprintf("the error advice target must be a non-nil pointer, but now is [%T]", target))
+	}
+	if elem := tp.Elem(); elem.Kind() != reflect.Interface && !elem.Implements(typeError) {
+		panic(fmt.Sprintf("the error advice target [%T] must point to an interface or a type implementing error", target))
+	}
+	if handler == nil {
+		panic("the error advice handler can not be nil")
+	}
+	return &ErrorAdvice{
+		target:  tp.Elem(),
+		handler: handler,
+	}
+}
+
+// Matches 错误链中是否有该类型的错误
+func (ea *ErrorAdvice) Matches(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.As(err, reflect.New(ea.target).Interface())
+}
+
+// Handle 处理错误
+func (ea *ErrorAdvice) Handle(writer http.ResponseWriter, request *http.Request, err error) {
+	ea.handler(writer, request, err)
+}
+
+// MatchErrorAdvice 按顺序查找第一个匹配的错误处理器，都不匹配时返回 nil
+func MatchErrorAdvice(err error, advices ...[]*ErrorAdvice) *ErrorAdvice {
+	for _, group := range advices {
+		for _, advice := range group {
+			if advice.Matches(err) {
+				return advice
+			}
+		}
+	}
+	return nil
+}
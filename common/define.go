@@ -27,7 +27,9 @@ import (
 
 // 定义一些常量
 const (
+	// Deprecated: 过滤器链使用每个请求各自的迭代器，不再记录索引
 	RequestFilterIndexName = "gox-filter-index" // 每个请求过滤器索引名字
+	RequestContextName     = "gox-context"      // 每个请求所属的 GoX 上下文
 )
 
 // AttributeKey request 属性的键类型
@@ -21,19 +21,199 @@
 package common
 
 import (
-	"errors"
+	"net/http"
 )
 
 // HTTPError HTTP 异常定义
+//
+// 处理器直接返回或者包装后返回（errors.As 可识别）时，按其状态码、响应头和描述响应
+// 默认的异常处理器以 RFC 7807 application/problem+json 格式输出
 type HTTPError struct {
-	Code  int   // 状态码
-	Error error // 错误信息
+	Status     int                    // 状态码
+	Code       string                 // 业务错误码，可为空
+	Type       string                 // 问题类型 URI，为空时为 about:blank
+	Title      string                 // 简短描述，为空时为状态码对应的描述
+	Detail     string                 // 详细描述
+	Instance   string                 // 出现问题的资源 URI
+	Header     http.Header            // 额外的响应头
+	Extensions map[string]interface{} // 扩展字段
+	Err        error                  // 原始错误
 }
 
 // NewHTTPError 一个新的异常
-func NewHTTPError(statusCode int, error string) *HTTPError {
+func NewHTTPError(status int, detail string) *HTTPError {
 	return &HTTPError{
-		Code:  statusCode,
-		Error: errors.New(error),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// WrapHTTPError 用状态码包装错误，详细描述为原始错误信息
+func WrapHTTPError(status int, err error) *HTTPError {
+	he := &HTTPError{
+		Status: status,
+		Err:    err,
+	}
+	if err != nil {
+		he.Detail = err.Error()
+	}
+	return he
+}
+
+// NewHTTPErrorWithError 按旧版 HTTPError{Code: 状态码, Error: 错误} 的结构创建异常
+//
+// Deprecated: 旧版的 Code 字段（状态码）已改为 Status，Error 字段已改为 Err，Code 现在是字符串类型的业务错误码，
+// 请使用 WrapHTTPError(status, err)
+func NewHTTPErrorWithError(code int, err error) *HTTPError {
+	return WrapHTTPError(code, err)
+}
+
+// BadRequest 400 异常
+func BadRequest(detail string) *HTTPError {
+	return NewHTTPError(http.StatusBadRequest, detail)
+}
+
+// Unauthorized 401 异常
+func Unauthorized(detail string) *HTTPError {
+	return NewHTTPError(http.StatusUnauthorized, detail)
+}
+
+// Forbidden 403 异常
+func Forbidden(detail string) *HTTPError {
+	return NewHTTPError(http.StatusForbidden, detail)
+}
+
+// NotFound 404 异常
+func NotFound(detail string) *HTTPError {
+	return NewHTTPError(http.StatusNotFound, detail)
+}
+
+// Conflict 409 异常
+func Conflict(detail string) *HTTPError {
+	return NewHTTPError(http.StatusConflict, detail)
+}
+
+// InternalServerError 500 异常
+func InternalServerError(detail string) *HTTPError {
+	return NewHTTPError(http.StatusInternalServerError, detail)
+}
+
+// WithCode 设置业务错误码
+func (he *HTTPError) WithCode(code string) *HTTPError {
+	he.Code = code
+	return he
+}
+
+// WithType 设置问题类型 URI
+func (he *HTTPError) WithType(tp string) *HTTPError {
+	he.Type = tp
+	return he
+}
+
+// WithTitle 设置简短描述
+func (he *HTTPError) WithTitle(title string) *HTTPError {
+	he.Title = title
+	return he
+}
+
+// WithHeader 添加响应头
+func (he *HTTPError) WithHeader(key, value string) *HTTPError {
+	if he.Header == nil {
+		he.Header = make(http.Header)
+	}
+	he.Header.Add(key, value)
+	return he
+}
+
+// WithExtension 添加扩展字段
+func (he *HTTPError) WithExtension(key string, value interface{}) *HTTPError {
+	if he.Extensions == nil {
+		he.Extensions = make(map[string]interface{})
+	}
+	he.Extensions[key] = value
+	return he
+}
+
+// WithErr 设置原始错误
+func (he *HTTPError) WithErr(err error) *HTTPError {
+	he.Err = err
+	return he
+}
+
+// Error 错误信息，依次为详细描述、原始错误信息和简短描述
+func (he *HTTPError) Error() string {
+	switch {
+	case he.Detail != "":
+		return he.Detail
+	case he.Err != nil:
+		return he.Err.Error()
+	}
+	return he.GetTitle()
+}
+
+// Unwrap 原始错误
+func (he *HTTPError) Unwrap() error {
+	return he.Err
+}
+
+// GetTitle 简短描述，为空时为状态码对应的描述
+func (he *HTTPError) GetTitle() string {
+	if he.Title != "" {
+		return he.Title
+	}
+	return http.StatusText(he.Status)
+}
+
+// Problem RFC 7807 格式的问题详情，扩展字段与标准字段同级
+func (he *HTTPError) Problem() map[string]interface{} {
+	problem := make(map[string]interface{}, len(he.Extensions)+6)
+	for key, value := range he.Extensions {
+		problem[key] = value
+	}
+	problem["type"] = "about:blank"
+	if he.Type != "" {
+		problem["type"] = he.Type
+	}
+	problem["title"] = he.GetTitle()
+	problem["status"] = he.Status
+	if detail := he.Error(); detail != problem["title"] {
+		problem["detail"] = detail
+	}
+	if he.Instance != "" {
+		problem["instance"] = he.Instance
+	}
+	if he.Code != "" {
+		problem["code"] = he.Code
+	}
+	return problem
+}
+
+// PanicError 处理请求时发生的 panic
+type PanicError struct {
+	Value   interface{} // panic 的值
+	Stack   []byte      // 调用栈
+	Route   string      // 匹配到的路由，未匹配到时为空
+	Handled bool        // 是否已交给异常处理器处理
+}
+
+// NewPanicError 一个新的 panic 异常
+func NewPanicError(value interface{}, stack []byte, route string) *PanicError {
+	return &PanicError{
+		Value: value,
+		Stack: stack,
+		Route: route,
+	}
+}
+
+// Error 通用的错误信息，避免向客户端暴露 panic 详情
+func (pe *PanicError) Error() string {
+	return http.StatusText(http.StatusInternalServerError)
+}
+
+// Unwrap panic 的值是 error 时返回该值
+func (pe *PanicError) Unwrap() error {
+	if err, ok := pe.Value.(error); ok {
+		return err
 	}
+	return nil
 }
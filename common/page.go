@@ -0,0 +1,186 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 11:20 上午
+// version: 1.0.0
+// desc   : 分页和排序
+//			处理器的 Pageable 参数从 page、size 和 sort 请求参数中自动装配
+
+package common
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageSize = 20  // 默认每页条数
+	MaxPageSize     = 100 // 默认的最大每页条数
+)
+
+// Order 排序字段
+type Order struct {
+	Property string `json:"property"` // 字段名
+	Desc     bool   `json:"desc"`     // 是否降序
+}
+
+// String 格式化为 property,asc 或 property,desc
+func (o Order) String() string {
+	if o.Desc {
+		return o.Property + ",desc"
+	}
+	return o.Property + ",asc"
+}
+
+// Pageable 分页请求
+//
+// page 从 1 开始，sort 格式为 field、field,asc 或 field,desc，可以有多个
+// 如 ?page=2&size=10&sort=age,desc&sort=name
+type Pageable struct {
+	Page int     // 页码，从 1 开始
+	Size int     // 每页条数
+	Sort []Order // 排序字段
+}
+
+// ParsePageable 从请求参数中解析分页请求
+//
+// size 为空时使用 defaultSize，超过 maxSize 时使用 maxSize
+func ParsePageable(values url.Values, defaultSize, maxSize int) (Pageable, error) {
+	pageable := Pageable{Page: 1, Size: defaultSize}
+
+	if page := values.Get("page"); page != "" {
+		num, err := strconv.Atoi(page)
+		if err != nil || num < 1 {
+			return pageable, fmt.Errorf("The param [page] expects a positive integer, but received [%v].", page)
+		}
+		pageable.Page = num
+	}
+
+	if size := values.Get("size"); size != "" {
+		num, err := strconv.Atoi(size)
+		if err != nil || num < 1 {
+			return pageable, fmt.Errorf("The param [size] expects a positive integer, but received [%v].", size)
+		}
+		pageable.Size = num
+	}
+	if maxSize > 0 && pageable.Size > maxSize {
+		pageable.Size = maxSize
+	}
+
+	for _, sort := range values["sort"] {
+		orders, err := parseOrders(sort)
+		if err != nil {
+			return pageable, err
+		}
+		pageable.Sort = append(pageable.Sort, orders...)
+	}
+	return pageable, nil
+}
+
+// parseOrders 解析排序字段
+//
+// 最后的 asc 或 desc 作用于之前的所有字段，如 name,age,desc
+func parseOrders(sort string) ([]Order, error) {
+	parts := strings.Split(sort, ",")
+	desc := false
+	switch strings.ToLower(strings.TrimSpace(parts[len(parts)-1])) {
+	case "desc":
+		desc = true
+		parts = parts[:len(parts)-1]
+	case "asc":
+		parts = parts[:len(parts)-1]
+	}
+
+	orders := make([]Order, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			orders = append(orders, Order{Property: part, Desc: desc})
+		}
+	}
+	if len(orders) == 0 {
+		return nil, fmt.Errorf("The param [sort] expects format [field,asc|desc], but received [%v].", sort)
+	}
+	return orders, nil
+}
+
+// Offset 偏移量，便于查询数据库
+func (p Pageable) Offset() int {
+	return (p.Page - 1) * p.Size
+}
+
+// Page 分页结果
+//
+// 响应时设置 X-Total-Count 和 Link 响应头，Link 中包含 first、prev、next 和 last 页的地址
+type Page struct {
+	Content    interface{} `json:"content"`    // 当前页数据
+	Page       int         `json:"page"`       // 页码，从 1 开始
+	Size       int         `json:"size"`       // 每页条数
+	Total      int64       `json:"total"`      // 总条数
+	TotalPages int         `json:"totalPages"` // 总页数
+	Sort       []Order     `json:"sort,omitempty"`
+}
+
+// NewPage 一个新的分页结果
+func NewPage(content interface{}, pageable Pageable, total int64) *Page {
+	page := &Page{
+		Content: content,
+		Page:    pageable.Page,
+		Size:    pageable.Size,
+		Total:   total,
+		Sort:    pageable.Sort,
+	}
+	if page.Size > 0 {
+		page.TotalPages = int((total + int64(page.Size) - 1) / int64(page.Size))
+	}
+	return page
+}
+
+// HasPrevious 是否有上一页
+func (p *Page) HasPrevious() bool {
+	return p.Page > 1
+}
+
+// HasNext 是否有下一页
+func (p *Page) HasNext() bool {
+	return p.Page < p.TotalPages
+}
+
+// Links 生成 Link 响应头，链接保留其他请求参数
+func (p *Page) Links(u *url.URL) string {
+	links := make([]string, 0, 4)
+	link := func(page int, rel string) {
+		query := u.Query()
+		query.Set("page", strconv.Itoa(page))
+		query.Set("size", strconv.Itoa(p.Size))
+		links = append(links, fmt.Sprintf(`<%s?%s>; rel="%s"`, u.Path, query.Encode(), rel))
+	}
+
+	if p.TotalPages > 0 {
+		link(1, "first")
+	}
+	if p.HasPrevious() {
+		link(p.Page-1, "prev")
+	}
+	if p.HasNext() {
+		link(p.Page+1, "next")
+	}
+	if p.TotalPages > 0 {
+		link(p.TotalPages, "last")
+	}
+	return strings.Join(links, ", ")
+}
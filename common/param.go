@@ -24,14 +24,18 @@ import "reflect"
 
 // Param 参数信息定义
 type Param struct {
-	Name     string       // 参数名
-	Required bool         // 是否必须
-	InHeader bool         // 是否在 header 中，普通 header 参数
-	InPath   bool         // 是否在 path 中，RESTful 参数
-	IsBody   bool         // 是否在 body 中，RequestBody 参数
-	RealType reflect.Type // 参数的实际类型
-	IsPtr    bool         // 参数是否是指针
-	ElemType reflect.Type // 如果实际类型是指针，这里记录指针所指向的类型
+	Name        string       // 参数名
+	Required    bool         // 是否必须
+	InHeader    bool         // 是否在 header 中，普通 header 参数
+	InPath      bool         // 是否在 path 中，RESTful 参数
+	InCookie    bool         // 是否在 cookie 中，接收 cookie 值或者 *http.Cookie
+	IsBody      bool         // 是否在 body 中，RequestBody 参数
+	InBean      bool         // 是否在 IOC 容器中，名称为空时按类型查找
+	InAttribute bool         // 是否在 request 属性中，由过滤器或拦截器设置
+	RealType    reflect.Type // 参数的实际类型
+	IsPtr       bool         // 参数是否是指针
+	ElemType    reflect.Type // 如果实际类型是指针，这里记录指针所指向的类型
+	Validations []string     // 校验规则，如 min=1、enum=a|b|c
 }
 
 // NewParam 一个新的参数
@@ -0,0 +1,143 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 11:05 下午
+// version: 1.0.0
+// desc   : 处理器的响应类型
+//			处理器返回这些类型时，按其状态码、响应头等响应
+
+package common
+
+import (
+	"io"
+	"net/http"
+)
+
+// ResponseEntity 带状态码、响应头和 cookie 的响应
+//
+// Body 可以是任意类型，包括 Redirect、Stream、File 以外的其他响应类型
+type ResponseEntity struct {
+	Status  int            // 状态码，为 0 时为 200
+	Header  http.Header    // 响应头
+	Cookies []*http.Cookie // cookie
+	Body    interface{}    // 响应体，为 nil 时不响应 body
+}
+
+// NewResponseEntity 一个新的响应
+func NewResponseEntity(status int, body interface{}) *ResponseEntity {
+	return &ResponseEntity{
+		Status: status,
+		Body:   body,
+	}
+}
+
+// Ok 200 响应
+func Ok(body interface{}) *ResponseEntity {
+	return NewResponseEntity(http.StatusOK, body)
+}
+
+// Created 201 响应，location 为新资源的地址
+func Created(location string, body interface{}) *ResponseEntity {
+	return NewResponseEntity(http.StatusCreated, body).WithHeader("Location", location)
+}
+
+// Accepted 202 响应
+func Accepted(body interface{}) *ResponseEntity {
+	return NewResponseEntity(http.StatusAccepted, body)
+}
+
+// NoContent 204 响应
+func NoContent() *ResponseEntity {
+	return NewResponseEntity(http.StatusNoContent, nil)
+}
+
+// WithHeader 添加响应头
+func (re *ResponseEntity) WithHeader(key, value string) *ResponseEntity {
+	if re.Header == nil {
+		re.Header = make(http.Header)
+	}
+	re.Header.Add(key, value)
+	return re
+}
+
+// WithCookie 添加 cookie
+func (re *ResponseEntity) WithCookie(cookie *http.Cookie) *ResponseEntity {
+	re.Cookies = append(re.Cookies, cookie)
+	return re
+}
+
+// Redirect 重定向
+type Redirect struct {
+	Status   int    // 状态码，为 0 时为 302
+	Location string // 重定向地址
+}
+
+// NewRedirect 302 重定向
+func NewRedirect(location string) *Redirect {
+	return &Redirect{
+		Status:   http.StatusFound,
+		Location: location,
+	}
+}
+
+// NewPermanentRedirect 301 重定向
+func NewPermanentRedirect(location string) *Redirect {
+	return &Redirect{
+		Status:   http.StatusMovedPermanently,
+		Location: location,
+	}
+}
+
+// Stream 流式响应，Reader 实现了 io.Closer 时响应完成后自动关闭
+type Stream struct {
+	Reader      io.Reader // 响应内容
+	ContentType string    // 为空时为 application/octet-stream
+}
+
+// NewStream 一个新的流式响应
+func NewStream(reader io.Reader, contentType string) *Stream {
+	return &Stream{
+		Reader:      reader,
+		ContentType: contentType,
+	}
+}
+
+// File 文件下载
+//
+// Path 不为空时响应该文件，支持 Range 请求（作为 ResponseEntity 的 Body 且状态码不是 200 时除外）；否则响应 Reader 中的内容
+type File struct {
+	Path        string    // 文件路径
+	Reader      io.Reader // 文件内容，Path 为空时使用，实现了 io.Closer 时响应完成后自动关闭
+	Name        string    // 下载的文件名，为空时为 Path 中的文件名
+	ContentType string    // 为空时按文件名推断
+	Inline      bool      // 是否在浏览器中直接打开
+}
+
+// NewFile 下载文件
+func NewFile(path, name string) *File {
+	return &File{
+		Path: path,
+		Name: name,
+	}
+}
+
+// NewFileReader 下载 Reader 中的内容
+func NewFileReader(reader io.Reader, name string) *File {
+	return &File{
+		Reader: reader,
+		Name:   name,
+	}
+}
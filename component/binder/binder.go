@@ -0,0 +1,437 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 3:20 下午
+// version: 1.0.0
+// desc   : 请求参数装配器
+//			按字段标签从 path、query、header、cookie、form 和 json body 中装配 VO
+
+package binder
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/yhyzgn/gox/util"
+)
+
+const (
+	TagPath     = "path"     // path 参数
+	TagQuery    = "query"    // URL query 参数
+	TagHeader   = "header"   // 请求头
+	TagCookie   = "cookie"   // cookie
+	TagForm     = "form"     // 表单参数，包括 multipart 表单
+	TagParam    = "param"    // 普通参数，先 query 后 form
+	TagRequired = "required" // 是否必需
+	TagLayout   = "layout"   // time.Time 的时间格式
+	TagJSON     = "json"     // json body 字段
+)
+
+var (
+	// 按优先级排列的参数来源
+	sources = []string{TagPath, TagHeader, TagCookie, TagForm, TagQuery, TagParam}
+
+	typeTime            = reflect.TypeOf(time.Time{})
+	typeCookie          = reflect.TypeOf(http.Cookie{})
+	typeTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// binder 一次请求的装配过程
+type binder struct {
+	request   *http.Request
+	variables map[string]string
+	parsed    bool
+	parseErr  error // 解析表单时的错误
+	jsonBody  bool  // 是否已从 json body 中装配
+}
+
+// Bind 按字段标签从请求中装配结构体
+//
+// target 必须是结构体指针，字段标签：
+//
+//	path:"id"       -> path 参数
+//	query:"q"       -> URL query 参数
+//	header:"Token"  -> 请求头
+//	cookie:"sid"    -> cookie，支持 string、http.Cookie 和 *http.Cookie
+//	form:"f"        -> 表单参数
+//	param:"name"    -> 先 query 后 form，未配置任何标签时按字段名获取
+//	json:"name"     -> json body 字段，请求不是 json body 时和 param 一样获取
+//	required:""     -> 必需参数
+//	layout:"2006-01-02" -> time.Time 的时间格式
+//
+// 支持嵌套结构体、切片（?tag=a&tag=b）、map（?m[k]=v）、指针（未传时为 nil）、
+// time.Time、time.Duration 和 encoding.TextUnmarshaler
+func Bind(request *http.Request, variables map[string]string, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("the binding target must be struct pointer, but now is [%T]", target)
+	}
+	b := &binder{
+		request:   request,
+		variables: variables,
+	}
+	if err := b.bindBody(target); err != nil {
+		return err
+	}
+	if err := b.bindStruct(value.Elem()); err != nil {
+		return err
+	}
+	// 请求体超过限制时，表单参数不完整
+	if errors.Is(b.parseErr, util.ErrBodyTooLarge) {
+		return b.parseErr
+	}
+	return nil
+}
+
+// bindBody 有 json 字段时，先解析 json body
+func (b *binder) bindBody(target interface{}) error {
+	if !hasJSONField(reflect.TypeOf(target).Elem()) || !isJSONRequest(b.request) {
+		return nil
+	}
+	bs, err := util.ReadRequestBody(b.request)
+	if err != nil {
+		return err
+	}
+	if len(bs) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(bs, target); err != nil {
+		return fmt.Errorf("can not decode request body: %v", err)
+	}
+	b.jsonBody = true
+	return nil
+}
+
+// bindStruct 逐个装配结构体字段
+func (b *binder) bindStruct(value reflect.Value) error {
+	tp := value.Type()
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		fieldValue := value.Field(i)
+
+		source, names := fieldSource(field)
+		if source == "" {
+			// 未配置来源的嵌套结构体，逐层装配
+			if isNestedStruct(field.Type) {
+				if err := b.bindNested(fieldValue); err != nil {
+					return err
+				}
+				continue
+			}
+			if isJSONField(field) {
+				if b.jsonBody {
+					// 有 json body 时只从 body 中获取
+					if _, required := field.Tag.Lookup(TagRequired); required && fieldValue.IsZero() {
+						return requiredError(names[0])
+					}
+					continue
+				}
+				// 没有 json body 时和普通参数一样，从 query 或 form 中获取
+				names = append(jsonNames(field), names...)
+			}
+			source = TagParam
+		}
+
+		if err := b.bindField(field, fieldValue, source, names); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindNested 装配嵌套结构体，指针为 nil 时自动创建
+func (b *binder) bindNested(value reflect.Value) error {
+	if value.Kind() != reflect.Ptr {
+		return b.bindStruct(value)
+	}
+	if value.IsNil() {
+		temp := reflect.New(value.Type().Elem())
+		if err := b.bindStruct(temp.Elem()); err != nil {
+			return err
+		}
+		util.FieldSet(value, temp)
+		return nil
+	}
+	return b.bindStruct(value.Elem())
+}
+
+// bindField 装配单个字段
+func (b *binder) bindField(field reflect.StructField, value reflect.Value, source string, names []string) error {
+	_, required := field.Tag.Lookup(TagRequired)
+
+	// cookie 对象
+	if source == TagCookie && (elemType(field.Type) == typeCookie) {
+		for _, name := range names {
+			if cookie, err := b.request.Cookie(name); err == nil {
+				if field.Type.Kind() == reflect.Ptr {
+					util.FieldSet(value, reflect.ValueOf(cookie))
+				} else {
+					util.FieldSet(value, reflect.ValueOf(*cookie))
+				}
+				return nil
+			}
+		}
+		if required {
+			return requiredError(names[0])
+		}
+		return nil
+	}
+
+	// map 字段，如 ?m[k]=v
+	if field.Type.Kind() == reflect.Map {
+		return b.bindMap(field, value, source, names, required)
+	}
+
+	var values []string
+	for _, name := range names {
+		if values = b.lookup(source, name); hasValue(values) {
+			break
+		}
+	}
+	if !hasValue(values) {
+		if required && value.IsZero() {
+			return requiredError(names[0])
+		}
+		return nil
+	}
+
+	converted, err := convert(values, field.Type, field.Tag.Get(TagLayout))
+	if err != nil {
+		return conversionError(names[0], err)
+	}
+	util.FieldSet(value, converted)
+	return nil
+}
+
+// bindMap 装配 map 字段，key 只支持 string 类型
+func (b *binder) bindMap(field reflect.StructField, value reflect.Value, source string, names []string, required bool) error {
+	if field.Type.Key().Kind() != reflect.String {
+		return fmt.Errorf("the key of map field [%v] must be string", field.Name)
+	}
+
+	var all url.Values
+	switch source {
+	case TagQuery:
+		all = b.request.URL.Query()
+	case TagForm:
+		b.parseForm()
+		all = b.request.PostForm
+	case TagParam:
+		b.parseForm()
+		all = b.request.Form
+	default:
+		return fmt.Errorf("the map field [%v] only supports query, form and param", field.Name)
+	}
+
+	result := reflect.MakeMap(field.Type)
+	for _, name := range names {
+		prefix := name + "["
+		for key, values := range all {
+			if !strings.HasPrefix(key, prefix) || !strings.HasSuffix(key, "]") || !hasValue(values) {
+				continue
+			}
+			item, err := convert(values, field.Type.Elem(), field.Tag.Get(TagLayout))
+			if err != nil {
+				return conversionError(key, err)
+			}
+			result.SetMapIndex(reflect.ValueOf(key[len(prefix):len(key)-1]).Convert(field.Type.Key()), item)
+		}
+		if result.Len() > 0 {
+			break
+		}
+	}
+
+	if result.Len() == 0 {
+		if required && value.IsZero() {
+			return requiredError(names[0])
+		}
+		return nil
+	}
+	util.FieldSet(value, result)
+	return nil
+}
+
+// lookup 从指定来源获取参数值
+func (b *binder) lookup(source, name string) []string {
+	switch source {
+	case TagPath:
+		if value, ok := b.variables[name]; ok {
+			return []string{value}
+		}
+	case TagHeader:
+		return b.request.Header[http.CanonicalHeaderKey(name)]
+	case TagCookie:
+		if cookie, err := b.request.Cookie(name); err == nil {
+			return []string{cookie.Value}
+		}
+	case TagForm:
+		b.parseForm()
+		return b.request.PostForm[name]
+	case TagQuery:
+		return b.request.URL.Query()[name]
+	default:
+		b.parseForm()
+		return b.request.Form[name]
+	}
+	return nil
+}
+
+// parseForm 解析表单参数，只解析一次
+func (b *binder) parseForm() {
+	if b.parsed {
+		return
+	}
+	b.parsed = true
+	if err := b.request.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		b.parseErr = err
+		_ = b.request.ParseForm()
+	}
+}
+
+// convert 按字段类型转换参数值
+func convert(values []string, tp reflect.Type, layout string) (reflect.Value, error) {
+	switch {
+	case tp.Kind() == reflect.Ptr:
+		// 指针，转换后取地址
+		elem, err := convert(values, tp.Elem(), layout)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(tp.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case tp.Kind() == reflect.Slice && tp.Elem().Kind() != reflect.Uint8:
+		// 切片，逐个转换
+		slice := reflect.MakeSlice(tp, 0, len(values))
+		for _, value := range values {
+			item, err := convert([]string{value}, tp.Elem(), layout)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			slice = reflect.Append(slice, item)
+		}
+		return slice, nil
+	case tp == typeTime && layout != "":
+		tm, err := time.Parse(layout, values[0])
+		if err != nil {
+			return reflect.Value{}, &util.ConversionError{Type: tp, Value: values[0], Err: err}
+		}
+		return reflect.ValueOf(tm), nil
+	default:
+		return util.ConvertValue(tp, values[0])
+	}
+}
+
+// fieldSource 获取字段的参数来源和参数名
+//
+// 参数名为空时，依次使用 param 标签、首字母小写的字段名和字段名
+func fieldSource(field reflect.StructField) (string, []string) {
+	names := make([]string, 0, 3)
+	if name := field.Tag.Get(TagParam); name != "" {
+		names = append(names, name)
+	}
+	names = append(names, util.FirstToLower(field.Name), field.Name)
+
+	for _, source := range sources {
+		if name, ok := field.Tag.Lookup(source); ok {
+			if name != "" && source != TagParam {
+				return source, []string{name}
+			}
+			return source, names
+		}
+	}
+	return "", names
+}
+
+// isNestedStruct 是否是需要逐层装配的嵌套结构体
+func isNestedStruct(tp reflect.Type) bool {
+	elem := elemType(tp)
+	return elem.Kind() == reflect.Struct && elem != typeTime && elem != typeCookie && !reflect.PtrTo(elem).Implements(typeTextUnmarshaler)
+}
+
+// hasJSONField 结构体中是否有 json 字段，包括嵌套结构体
+func hasJSONField(tp reflect.Type) bool {
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		if isJSONField(field) {
+			return true
+		}
+		if source, _ := fieldSource(field); source == "" && isNestedStruct(field.Type) && hasJSONField(elemType(field.Type)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isJSONField 是否是 json body 字段
+func isJSONField(field reflect.StructField) bool {
+	name, ok := field.Tag.Lookup(TagJSON)
+	return ok && name != "-"
+}
+
+// jsonNames json 标签中的字段名
+func jsonNames(field reflect.StructField) []string {
+	if name := strings.Split(field.Tag.Get(TagJSON), ",")[0]; name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+// isJSONRequest 请求体是否是 json
+func isJSONRequest(request *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+// hasValue 是否获取到非空参数值
+func hasValue(values []string) bool {
+	for _, value := range values {
+		if value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// elemType 指针类型获取其指向的类型
+func elemType(tp reflect.Type) reflect.Type {
+	if tp.Kind() == reflect.Ptr {
+		return tp.Elem()
+	}
+	return tp
+}
+
+// requiredError 必需参数缺失
+func requiredError(name string) error {
+	return fmt.Errorf("The param [%v] is required, but no value received.", name)
+}
+
+// conversionError 参数转换错误，记录参数名
+func conversionError(name string, err error) error {
+	if ce, ok := err.(*util.ConversionError); ok {
+		ce.Name = name
+		return ce
+	}
+	return fmt.Errorf("the param [%v] can not be converted: %v", name, err)
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 4:05 下午
+// version: 1.0.0
+// desc   : 请求参数装配器测试
+
+package binder
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Paging struct {
+	Page int `query:"page"`
+	Size int `query:"size"`
+}
+
+type Search struct {
+	ID       int64             `path:"id"`
+	Keyword  string            `query:"q" required:""`
+	Tags     []string          `query:"tag"`
+	Filters  map[string]int    `query:"filter"`
+	Token    string            `header:"X-Token"`
+	Session  string            `cookie:"sid"`
+	Cookie   *http.Cookie      `cookie:"sid"`
+	Since    time.Time         `query:"since" layout:"2006-01-02"`
+	Timeout  time.Duration     `query:"timeout"`
+	Limit    *int              `query:"limit"`
+	Name     string            `json:"name"`
+	Extra    map[string]string `json:"extra"`
+	Legacy   int               `param:"legacy"`
+	Nickname string
+	Paging
+}
+
+func TestBind(t *testing.T) {
+	request := httptest.NewRequest(http.MethodPost, "/users/12?q=gox&tag=a&tag=b&filter[age]=18&since=2020-05-13&timeout=3s&legacy=7&nickname=yhy&page=2&size=20", strings.NewReader(`{"name":"jason","extra":{"k":"v"}}`))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Token", "token")
+	request.AddCookie(&http.Cookie{Name: "sid", Value: "session"})
+
+	search := new(Search)
+	if err := Bind(request, map[string]string{"id": "12"}, search); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Search{
+		ID:       12,
+		Keyword:  "gox",
+		Tags:     []string{"a", "b"},
+		Filters:  map[string]int{"age": 18},
+		Token:    "token",
+		Session:  "session",
+		Since:    time.Date(2020, 5, 13, 0, 0, 0, 0, time.UTC),
+		Timeout:  3 * time.Second,
+		Name:     "jason",
+		Extra:    map[string]string{"k": "v"},
+		Legacy:   7,
+		Nickname: "yhy",
+		Paging:   Paging{Page: 2, Size: 20},
+	}
+	if search.Cookie == nil || search.Cookie.Value != "session" {
+		t.Errorf("cookie should be bound, but %v", search.Cookie)
+	}
+	search.Cookie = nil
+	if !reflect.DeepEqual(*search, expected) {
+		t.Errorf("should be bound as\n%+v\nbut\n%+v", expected, *search)
+	}
+}
+
+func TestBind_Error(t *testing.T) {
+	cases := map[string]string{
+		"required":   "/users/12",
+		"conversion": "/users/12?q=gox&limit=abc",
+	}
+	for name, target := range cases {
+		request := httptest.NewRequest(http.MethodGet, target, nil)
+		if err := Bind(request, map[string]string{"id": "12"}, new(Search)); err == nil {
+			t.Errorf("%v error should be reported", name)
+		}
+	}
+}
+
+type Profile struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+	Nick string `json:"nick_name"`
+}
+
+func TestBind_JSONFieldWithoutBody(t *testing.T) {
+	expected := Profile{Name: "gox", Age: 3, Nick: "x"}
+
+	request := httptest.NewRequest(http.MethodGet, "/profile?name=gox&age=3&nick_name=x", nil)
+	profile := new(Profile)
+	if err := Bind(request, nil, profile); err != nil || *profile != expected {
+		t.Errorf("json fields should be bound from query as %+v, but %+v, %v", expected, *profile, err)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/profile", strings.NewReader("name=gox&age=3&nick_name=x"))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	profile = new(Profile)
+	if err := Bind(request, nil, profile); err != nil || *profile != expected {
+		t.Errorf("json fields should be bound from form as %+v, but %+v, %v", expected, *profile, err)
+	}
+}
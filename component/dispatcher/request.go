@@ -21,30 +21,77 @@
 package dispatcher
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"reflect"
-	"regexp"
 	"runtime"
+	"runtime/debug"
 	"strings"
 
 	"github.com/yhyzgn/gog"
+	"github.com/yhyzgn/gox/codec"
 	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/component/binder"
 	"github.com/yhyzgn/gox/component/interceptor"
+	"github.com/yhyzgn/gox/component/validator"
 	"github.com/yhyzgn/gox/ctx"
+	"github.com/yhyzgn/gox/ioc"
+	"github.com/yhyzgn/gox/resolver"
 	"github.com/yhyzgn/gox/util"
 	"github.com/yhyzgn/gox/wire"
 )
 
+var (
+	// 可以携带请求体的请求方法
+	bodyMethods = []common.Method{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+	typeReader      = reflect.TypeOf((*io.Reader)(nil)).Elem()
+	typeReadCloser  = reflect.TypeOf((*io.ReadCloser)(nil)).Elem()
+	typeJSONDecoder = reflect.TypeOf(new(json.Decoder))
+	typeContext     = reflect.TypeOf((*context.Context)(nil)).Elem()
+	typePageable    = reflect.TypeOf(common.Pageable{})
+	typeCookie      = reflect.TypeOf(http.Cookie{})
+)
+
 // RequestDispatcher 请求分发器-实现类
 type RequestDispatcher struct {
+	context  *ctx.GoXContext
+	wires    *wire.Wires
 	register *interceptor.Register
+	provider *ioc.Provider
 }
 
 // NewRequestDispatcher 创建新的分发器
+//
+// 默认使用全局的上下文、映射集合和 IOC 容器，仅适用于默认服务 gox.Default()
+// 其他服务必须通过 SetContext、SetWires 和 SetProvider 配置各自的组件
 func NewRequestDispatcher() *RequestDispatcher {
-	return new(RequestDispatcher)
+	return &RequestDispatcher{
+		context:  ctx.C(),
+		wires:    wire.Instance,
+		provider: ioc.C(),
+	}
+}
+
+// SetContext 配置上下文
+func (rd *RequestDispatcher) SetContext(context *ctx.GoXContext) {
+	rd.context = context
+}
+
+// SetWires 配置处理器映射集合
+func (rd *RequestDispatcher) SetWires(wires *wire.Wires) {
+	rd.wires = wires
+}
+
+// SetProvider 配置 IOC 容器
+// 用于注入 Ship.Bean 参数
+func (rd *RequestDispatcher) SetProvider(provider *ioc.Provider) {
+	rd.provider = provider
 }
 
 // SetInterceptorRegister 配置拦截器注册器
@@ -63,22 +110,33 @@ func (rd *RequestDispatcher) Dispatch(writer http.ResponseWriter, request *http.
 	}
 
 	// 匹配路由
-	for _, h := range wire.Instance.All() {
-		// 如果直接完全匹配，说明不是 RESTful 模式
-		if reqPath == h.Path {
-			rd.doDispatch(h, writer, request, false)
+	if route := rd.wires.Match(reqPath); route != nil {
+		// 选择支持当前请求方法的处理器
+		if hw := route.Wire(request.Method); hw != nil {
+			rd.doDispatch(hw, route.Variables(hw), writer, request)
 			return
-		} else if util.IsRESTful(h.Path) {
-			// 否则 正则匹配
-			// 将 路由注册的路径 转换为 正则匹配模板，再看是否与真实路径匹配
-			realPathPattern := util.ConvertRESTfulPathToPattern(h.Path)
-			matched, err := regexp.MatchString(realPathPattern, reqPath)
-			if err == nil && matched {
-				// RESTful 匹配上了
-				rd.doDispatch(h, writer, request, true)
+		}
+
+		// HEAD 请求交给 GET 处理器，只是不响应 body
+		if request.Method == http.MethodHead {
+			if hw := route.Wire(http.MethodGet); hw != nil {
+				rd.doDispatch(hw, route.Variables(hw), newHeadResponseWriter(writer), request)
 				return
 			}
 		}
+
+		// 同一 path 上所有处理器支持的方法
+		util.SetResponseWriterHeader(writer, "Allow", joinMethods(route.Methods()))
+
+		// OPTIONS 请求直接响应支持的方法
+		if request.Method == http.MethodOptions {
+			writer.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		// 不支持的 http 方法
+		rd.context.GetUnSupportMethodHandler()(writer, request)
+		return
 	}
 
 	// 如果路由未找到，可能是静态资源
@@ -86,99 +144,120 @@ func (rd *RequestDispatcher) Dispatch(writer http.ResponseWriter, request *http.
 		// 默认首页
 		reqPath = "index.html"
 	}
-	filename := strings.ReplaceAll(ctx.C().GetStaticDir()+"/"+reqPath, "//", "/")
+	filename := strings.ReplaceAll(rd.context.GetStaticDir()+"/"+reqPath, "//", "/")
 	if util.FileExist(filename) {
 		http.ServeFile(writer, request, filename)
 		return
 	}
 
 	// 匹配不到，就只能 404 啦~
-	ctx.C().GetNotFoundHandler()(writer, request)
+	rd.context.GetNotFoundHandler()(writer, request)
 }
 
 // doDispatch 具体的请求分发操作
-func (rd *RequestDispatcher) doDispatch(hw *wire.HandlerWire, writer http.ResponseWriter, request *http.Request, isRESTful bool) {
-	md := VerifyMethod(hw, request.Method)
-	if !md {
-		// 不支持的 http 方法
-		ctx.C().GetUnSupportMethodHandler()(writer, request)
-		return
-	}
-
+func (rd *RequestDispatcher) doDispatch(hw *wire.HandlerWire, variables map[string]string, writer http.ResponseWriter, request *http.Request) {
 	// 处理器
 	handler := hw.Handler
 
+	var (
+		passed  []interceptor.Interceptor // PreHandle() 通过的拦截器
+		failure error                     // 处理过程中的错误或者 panic
+	)
+
+	// 最后逆序执行拦截器的 AfterCompletion() 方法
+	defer func() {
+		rd.afterCompletion(passed, writer, request, handler, failure)
+	}()
+
+	// 拦截器、处理器和结果处理器中的 panic 都转为 500
+	// 在闭包中读取 writer，以便用超时响应器或者拦截器替换后的 writer 响应
+	defer func() {
+		if value := recover(); value != nil {
+			rd.recoverPanic(hw, value, writer, request, &failure)
+		}
+	}()
+
 	// 匹配时忽略ContextPath
-	reqPath := strings.ReplaceAll(request.URL.Path, ctx.C().GetContextPath(), "")
+	reqPath := util.StripContextPath(request.URL.Path, rd.context.GetContextPath())
 
 	// 参数处理器
-	argumentResolver := ctx.C().GetArgumentResolver()
+	argumentResolver := rd.context.GetArgumentResolver()
 	// 结果处理器
-	resultResolver := ctx.C().GetResultResolver()
-	// 异常处理器
-	errorResolver := ctx.C().GetErrorResolver()
+	resultResolver := rd.context.GetResultResolver()
 
 	var (
 		res      reflect.Value
 		err      error
 		noResult bool
+		tw       *timeoutWriter
 	)
 
+	// 配置了超时时间，超时后取消 context.Context
+	if hw.Timeout > 0 {
+		c, cancel := context.WithTimeout(request.Context(), hw.Timeout)
+		defer cancel()
+		request = request.WithContext(c)
+		tw = newTimeoutWriter(writer)
+		writer = tw
+	}
+
+	// 限制请求体大小
+	if ex := rd.limitBody(hw, request); ex != nil {
+		gog.Error(ex)
+		rd.handleError(hw, ex.Status, ex, writer, request)
+		return
+	}
+
 	// 先处理一遍参数
-	args, ex := rd.resolve(hw, writer, request, isRESTful)
+	args, ex := rd.resolve(hw, variables, writer, request)
 	if ex != nil {
-		gog.Error(ex.Error)
-		errorResolver.Resolve(ex.Code, ex.Error, writer)
+		gog.Error(ex)
+		rd.handleError(hw, ex.Status, ex, writer, request)
 		return
 	}
 
 	// 再调用参数处理器处理
-	argumentResolver.Resolve(args, writer, request, handler)
+	if resolved := argumentResolver.Resolve(args, writer, request, handler); len(resolved) == len(args) {
+		args = resolved
+	}
 
 	gog.DebugF("Params of request path [{}] are [{}], matched router [{}] of params [{}]", request.URL.Path, util.FormatRealArgsValue(args), hw.Path, util.FormatHandlerArgs(hw.Params))
 
-	// 处理前，执行拦截器 PreHandle() 方法
-	if rd.register != nil && !util.IsExcludedRequest(reqPath, rd.register.GetExcludes()) {
-		passed, path := rd.register.Iterate(func(index int, path string, interceptor interceptor.Interceptor) (skipped, passed bool) {
-			// 匹配 path，未匹配到的直接跳过
-			if path == "/" {
-				// 所有请求
-				skipped = false
-				passed, request, writer = interceptor.PreHandle(writer, request, handler)
-			} else if path == reqPath {
-				// 严格匹配，只有路径完全相同才走过滤器
-				skipped = false
-				passed, request, writer = interceptor.PreHandle(writer, request, handler)
-			} else if util.MatchedRequestByPathPattern(reqPath, path) {
-				// 正则匹配成功，执行拦截器
-				skipped = false
-				passed, request, writer = interceptor.PreHandle(writer, request, handler)
-			} else {
-				// 跳过
-				skipped = true
-				passed = false
-			}
+	// 全局拦截器在前，分组拦截器在后
+	interceptors := rd.interceptors(hw, request, reqPath)
 
-			if skipped {
-				gog.TraceF("The request [%v] has skipped by interceptor [%v].", request.URL.Path, path)
-			} else if passed {
-				gog.TraceF("The request [%v] has passed by interceptor [%v].", request.URL.Path, path)
-			} else {
-				gog.TraceF("The request [%v] has been intercepted by interceptor [%v].", request.URL.Path, path)
-			}
-
-			return
-		})
-
-		// 拦截器不通过
-		if !passed {
-			gog.TraceF("The request [%v] has been intercepted by interceptor [%v].", request.URL.Path, path)
+	// 处理前，执行拦截器 PreHandle() 方法
+	for _, ipt := range interceptors {
+		var ok bool
+		ok, request, writer = ipt.PreHandle(writer, request, handler)
+		if !ok {
+			// 拦截器不通过，只有之前通过的拦截器执行 AfterCompletion()
+			gog.TraceF("The request [%v] has been intercepted by interceptor [%T].", request.URL.Path, ipt)
 			return
 		}
+		passed = append(passed, ipt)
+		gog.TraceF("The request [%v] has passed by interceptor [%T].", request.URL.Path, ipt)
 	}
 
-	// 将request和writer设置回请求中
+	// 将request、writer、context和属性设置回请求中
 	for i, arg := range args {
+		if hw.Params[i].RealType == typeContext {
+			// context.Context
+			args[i] = reflect.ValueOf(request.Context())
+			continue
+		}
+		if hw.Params[i].InAttribute {
+			// 拦截器设置的属性
+			val, ex := attribute(hw.Params[i], request)
+			if ex != nil {
+				gog.Error(ex)
+				failure = ex
+				rd.handleError(hw, ex.Status, ex, writer, request)
+				return
+			}
+			args[i] = val
+			continue
+		}
 		val := arg.Interface()
 		if val != nil {
 			// http.ResponseWriter || *http.Request
@@ -192,9 +271,32 @@ func (rd *RequestDispatcher) doDispatch(hw *wire.HandlerWire, writer http.Respon
 		}
 	}
 
+	// 超时或者客户端已断开，不再调用处理器
+	if rd.interrupted(hw, tw, writer, request) {
+		failure = request.Context().Err()
+		return
+	}
+
 	// 拦截器通过后，将请求交由 处理器 处理
 	// 已经获取到参数列表，执行方法即可
-	results := handler.Call(args)
+	var results []reflect.Value
+	if tw != nil {
+		var ok bool
+		if results, ok = rd.callWithTimeout(hw, args, request); !ok {
+			rd.interrupted(hw, tw, writer, request)
+			failure = request.Context().Err()
+			return
+		}
+	} else {
+		results = handler.Call(args)
+	}
+
+	// 处理过程中超时或者客户端已断开，不再响应
+	if rd.interrupted(hw, tw, writer, request) {
+		failure = request.Context().Err()
+		return
+	}
+
 	noResult = results == nil || len(results) == 0
 	if noResult {
 		// 无返回值
@@ -202,49 +304,258 @@ func (rd *RequestDispatcher) doDispatch(hw *wire.HandlerWire, writer http.Respon
 	} else {
 		// 响应结果交由 结果处理器 处理
 		res, err = resultResolver.Resolve(hw, results, writer, request)
-		// 如果有错误，就交给异常处理器处理
-		if err != nil {
-			errorResolver.Resolve(http.StatusOK, err, writer)
-			return
+	}
+
+	// 处理完成后，逆序执行拦截器的 PostHandle() 和 AfterHandle() 方法，处理器返回错误时也会执行
+	for i := len(passed) - 1; i >= 0; i-- {
+		if pi, ok := passed[i].(interceptor.PostInterceptor); ok {
+			res, err = pi.PostHandle(writer, request, handler, res, err)
 		}
+		request, writer = passed[i].AfterHandle(writer, request, handler, res, err)
 	}
 
-	// 处理完成后，执行拦截器的 AfterHandle() 方法
-	if rd.register != nil && !util.IsExcludedRequest(reqPath, rd.register.GetExcludes()) {
-		rd.register.ReverseIterate(func(index int, path string, interceptor interceptor.Interceptor) {
-			// 匹配 path，未匹配到的直接跳过
-			if path == "/" {
-				// 所有请求
-				request, writer = interceptor.AfterHandle(writer, request, handler, res, err)
-			} else if path == reqPath {
-				// 严格匹配，只有路径完全相同才走过滤器
-				request, writer = interceptor.AfterHandle(writer, request, handler, res, err)
-			} else if util.MatchedRequestByPathPattern(reqPath, path) {
-				// 正则匹配成功，执行拦截器
-				request, writer = interceptor.AfterHandle(writer, request, handler, res, err)
-			}
-		})
+	// 如果有错误，就交给异常处理器处理
+	// 返回 *common.HTTPError（或包装了它的错误）时使用其状态码，否则为 500
+	if err != nil {
+		failure = err
+		rd.handleError(hw, errorStatus(err, http.StatusInternalServerError), err, writer, request)
+		return
 	}
 
-	if !noResult {
+	// 处理器无返回值时，PostHandle() 也可以设置响应结果
+	if !noResult || res.IsValid() {
 		// 拦截器通过后，响应处理结果
-		resultResolver.Response(res, writer)
+		if err = resultResolver.Response(hw, res, writer, request); err != nil {
+			status := errorStatus(err, http.StatusInternalServerError)
+			if errors.Is(err, codec.ErrNotAcceptable) {
+				status = http.StatusNotAcceptable
+			}
+			gog.Error(err)
+			failure = err
+			rd.handleError(hw, status, err, writer, request)
+		}
+	}
+}
+
+// afterCompletion 逆序执行拦截器的 AfterCompletion() 方法
+func (rd *RequestDispatcher) afterCompletion(interceptors []interceptor.Interceptor, writer http.ResponseWriter, request *http.Request, handler common.Handler, err error) {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		if ci, ok := interceptors[i].(interceptor.CompletionInterceptor); ok {
+			complete(ci, writer, request, handler, err)
+		}
 	}
 }
 
+// complete 执行拦截器的 AfterCompletion() 方法，其中的 panic 只记录日志，不影响其他拦截器
+func complete(ci interceptor.CompletionInterceptor, writer http.ResponseWriter, request *http.Request, handler common.Handler, err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			gog.ErrorF("Panic occurred in AfterCompletion() of interceptor [%T]: %v\n%s", ci, value, debug.Stack())
+		}
+	}()
+	ci.AfterCompletion(writer, request, handler, err)
+}
+
+// recoverPanic 处理已恢复的 panic，记录路由信息后交给异常处理器响应 500
+//
+// 开启 RePanic 时继续抛出已处理的 *common.PanicError
+func (rd *RequestDispatcher) recoverPanic(hw *wire.HandlerWire, value interface{}, writer http.ResponseWriter, request *http.Request, failure *error) {
+	// 客户端已断开，交由 net/http 处理
+	if value == http.ErrAbortHandler {
+		*failure = http.ErrAbortHandler
+		panic(value)
+	}
+
+	// 超时处理时，处理器中的 panic 已在其 goroutine 中转为 *common.PanicError
+	pe, ok := value.(*common.PanicError)
+	if !ok {
+		pe = common.NewPanicError(value, debug.Stack(), hw.Path)
+	}
+	pe.Handled = true
+	*failure = pe
+	handlerName := runtime.FuncForPC(reflect.Value(hw.Handler).Pointer()).Name()
+	gog.ErrorF("Panic occurred while serving request [%v %v], matched router [%v] of handler [%v]: %v\n%s", request.Method, request.URL.Path, hw.Path, handlerName, pe.Value, pe.Stack)
+	rd.handleError(hw, http.StatusInternalServerError, pe, writer, request)
+
+	if rd.context.IsRePanic() {
+		panic(pe)
+	}
+}
+
+// callWithTimeout 在新的 goroutine 中调用处理器，直到处理完成或者超时
+//
+// 超时或者客户端断开时返回 false，处理器的 panic 转移到当前 goroutine 中抛出
+func (rd *RequestDispatcher) callWithTimeout(hw *wire.HandlerWire, args []reflect.Value, request *http.Request) ([]reflect.Value, bool) {
+	var (
+		results []reflect.Value
+		pe      *common.PanicError
+		done    = make(chan struct{})
+	)
+
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				pe = common.NewPanicError(value, debug.Stack(), hw.Path)
+			}
+			close(done)
+		}()
+		results = reflect.Value(hw.Handler).Call(args)
+	}()
+
+	select {
+	case <-done:
+		if pe != nil {
+			panic(pe)
+		}
+		return results, true
+	case <-request.Context().Done():
+		return nil, false
+	}
+}
+
+// interrupted 判断请求是否已被中断
+//
+// 超时时响应 503（可配置为 504），客户端已断开时不再响应
+func (rd *RequestDispatcher) interrupted(hw *wire.HandlerWire, tw *timeoutWriter, writer http.ResponseWriter, request *http.Request) bool {
+	switch err := request.Context().Err(); err {
+	case nil:
+		return false
+	case context.DeadlineExceeded:
+		gog.ErrorF("The request [%v %v] timed out, matched router [%v].", request.Method, request.URL.Path, hw.Path)
+		status := rd.context.GetTimeoutStatus()
+		if tw == nil {
+			rd.handleError(hw, status, common.NewHTTPError(status, "Request timed out."), writer, request)
+		} else if tw.timeout() {
+			// 处理器尚未响应时才能响应超时
+			rd.handleError(hw, status, common.NewHTTPError(status, "Request timed out."), tw.ResponseWriter, request)
+		}
+	default:
+		gog.DebugF("The client of request [{} {}] has gone away, skip response.", request.Method, request.URL.Path)
+	}
+	return true
+}
+
+// bean 从 IOC 容器获取参数
+//
+// 按名称查找单例，名称为空时按参数类型查找，按类型找到多个时需改为按名称查找
+func (rd *RequestDispatcher) bean(param *common.Param) (reflect.Value, *common.HTTPError) {
+	var bean interface{}
+	if param.Name != "" {
+		bean = rd.provider.GetSingle(param.Name)
+	} else {
+		// 按类型找到多个单例时无法确定使用哪一个，需改为按名称注入
+		var err error
+		if bean, err = rd.provider.LookupSingleByType(param.RealType); err != nil {
+			return reflect.Value{}, common.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("The bean of type [%v] can not be injected: %v", param.RealType, err))
+		}
+	}
+	if bean == nil {
+		return reflect.Value{}, common.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("The bean [%v] of type [%v] not found in IOC container.", param.Name, param.RealType))
+	}
+	val := reflect.ValueOf(bean)
+	if !val.Type().AssignableTo(param.RealType) {
+		return reflect.Value{}, common.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("The bean [%v] of type [%v] can not be used as [%v].", param.Name, val.Type(), param.RealType))
+	}
+	return val, nil
+}
+
+// handleError 处理异常
+//
+// 先按错误类型匹配路由所属分组的错误处理器，再匹配全局的错误处理器，都不匹配时交给异常处理器
+func (rd *RequestDispatcher) handleError(hw *wire.HandlerWire, status int, err error, writer http.ResponseWriter, request *http.Request) {
+	if advice := common.MatchErrorAdvice(err, hw.ErrorAdvices, rd.context.GetErrorAdvices()); advice != nil {
+		advice.Handle(writer, request, err)
+		return
+	}
+	rd.context.GetErrorResolver().Resolve(status, err, writer)
+}
+
+// interceptors 获取当前请求需要执行的所有拦截器
+func (rd *RequestDispatcher) interceptors(hw *wire.HandlerWire, request *http.Request, reqPath string) []interceptor.Interceptor {
+	interceptors := make([]interceptor.Interceptor, 0, len(hw.Interceptors))
+	if rd.register != nil {
+		interceptors = append(interceptors, rd.register.MatchedRequest(request, reqPath)...)
+	}
+	return append(interceptors, hw.Interceptors...)
+}
+
 // resolve 初步处理参数
-func (rd *RequestDispatcher) resolve(hw *wire.HandlerWire, writer http.ResponseWriter, request *http.Request, isRESTful bool) ([]reflect.Value, *common.HTTPError) {
-	path := request.URL.Path
+func (rd *RequestDispatcher) resolve(hw *wire.HandlerWire, variables map[string]string, writer http.ResponseWriter, request *http.Request) ([]reflect.Value, *common.HTTPError) {
 	handler := reflect.Value(hw.Handler)
 	handlerName := strings.ReplaceAll(runtime.FuncForPC(handler.Pointer()).Name(), "-fm", util.FormatHandlerArgs(hw.Params))
 
-	var pathVariables []string
-	if isRESTful {
-		pathVariables = util.GetRESTfulParams(hw.Path)
+	// 限制了请求体的媒体类型
+	if ex := checkConsumes(hw, request); ex != nil {
+		return nil, ex
 	}
+
 	args := make([]reflect.Value, 0)
 
+	paramResolvers := rd.context.GetParamResolvers()
+
 	for _, param := range hw.Params {
+		// ----------------------------------------------------------------------------------------------     custom     ----------------------------------------------------------------------------------------------
+		// 自定义的参数处理器优先
+		if pr := resolver.MatchParamResolver(param, paramResolvers); pr != nil {
+			val, ex := resolveParam(pr, param, writer, request)
+			if ex != nil {
+				return nil, ex
+			}
+			args = append(args, val)
+			continue
+		}
+
+		// ----------------------------------------------------------------------------------------------     context    ----------------------------------------------------------------------------------------------
+		// context.Context 来自于请求
+		if param.RealType == typeContext {
+			args = append(args, reflect.ValueOf(request.Context()))
+			continue
+		}
+
+		// ----------------------------------------------------------------------------------------------    Pageable    ----------------------------------------------------------------------------------------------
+		// 分页请求，从 page、size 和 sort 参数中装配
+		if param.ElemType == typePageable {
+			pageable, err := common.ParsePageable(request.URL.Query(), rd.context.GetPageSize(), rd.context.GetMaxPageSize())
+			if err != nil {
+				return nil, common.WrapHTTPError(http.StatusBadRequest, err)
+			}
+			if param.IsPtr {
+				args = append(args, reflect.ValueOf(&pageable))
+			} else {
+				args = append(args, reflect.ValueOf(pageable))
+			}
+			continue
+		}
+
+		// ----------------------------------------------------------------------------------------------       IOC      ----------------------------------------------------------------------------------------------
+		// 从 IOC 容器获取
+		if param.InBean {
+			val, ex := rd.bean(param)
+			if ex != nil {
+				return nil, ex
+			}
+			args = append(args, val)
+			continue
+		}
+
+		// ----------------------------------------------------------------------------------------------    Attribute   ----------------------------------------------------------------------------------------------
+		// request 属性在拦截器之后才能取值，这里先占位
+		if param.InAttribute {
+			args = append(args, reflect.Zero(param.RealType))
+			continue
+		}
+
+		// ----------------------------------------------------------------------------------------------     Cookie     ----------------------------------------------------------------------------------------------
+		// 从 cookie 获取
+		if param.InCookie {
+			val, ex := cookieParam(param, request)
+			if ex != nil {
+				return nil, ex
+			}
+			args = append(args, val)
+			continue
+		}
+
 		// ----------------------------------------------------------------------------------------------    net/http    ----------------------------------------------------------------------------------------------
 		// http.ResponseWriter || *http.Request
 		if param.ElemType.PkgPath() == "net/http" {
@@ -262,14 +573,19 @@ func (rd *RequestDispatcher) resolve(hw *wire.HandlerWire, writer http.ResponseW
 
 		// ----------------------------------------------------------------------------------------------     RESTful    ----------------------------------------------------------------------------------------------
 		// RESTful 格式传参
-		if param.InPath && pathVariables != nil {
-			index := util.GetPathVariableIndex(param.Name, hw.Path)
-			if index > -1 {
+		if param.InPath {
+			if temp, ok := variables[param.Name]; ok {
 				// 找到啦~
-				// 找到位置后，去 url path 的对应位置上获取参数值即可
-				temp := GetPathVariableValue(index, path)
+				// 路由匹配时已经取出了参数值，直接使用即可
+				val, ex := convertParam(param, temp)
+				if ex != nil {
+					return nil, ex
+				}
+				if ex := validate(param, val); ex != nil {
+					return nil, ex
+				}
 				// 添加到参数列表
-				args = append(args, util.StringToValue(param.RealType.Kind(), temp))
+				args = append(args, val)
 				continue
 			}
 			return nil, common.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The path [%v] does not contains path variable [%v].", hw.Path, param.Name))
@@ -282,57 +598,69 @@ func (rd *RequestDispatcher) resolve(hw *wire.HandlerWire, writer http.ResponseW
 			if temp == "" && param.Required {
 				return nil, common.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The param [%v] is required, but received value is empty.", param.Name))
 			}
+			val, ex := convertParam(param, temp)
+			if ex != nil {
+				return nil, ex
+			}
+			if ex := validate(param, val); ex != nil {
+				return nil, ex
+			}
 			// 添加到参数列表
-			args = append(args, util.StringToValue(param.RealType.Kind(), temp))
+			args = append(args, val)
 			continue
 		}
 
 		// ----------------------------------------------------------------------------------------------   RequestBody  ----------------------------------------------------------------------------------------------
 		// 有 requestBody 参数
-		// 仅支持 POST 和 PUT 方法
+		// 支持 POST、PUT、PATCH 和 DELETE 方法
 		if param.IsBody {
-			if request.Method != http.MethodPost && request.Method != http.MethodPut {
-				return nil, common.NewHTTPError(http.StatusMethodNotAllowed, fmt.Sprintf("RequestBody only support 'POST' and 'PUT' method, but now is [%v].", request.Method))
+			if !canCarryBody(request.Method) {
+				return nil, common.NewHTTPError(http.StatusMethodNotAllowed, fmt.Sprintf("RequestBody only support %v method, but now is [%v].", bodyMethods, request.Method))
 			}
 
-			if !VerifyMethod(hw, http.MethodPost) && !VerifyMethod(hw, http.MethodPut) {
-				return nil, common.NewHTTPError(http.StatusMethodNotAllowed, fmt.Sprintf("Maybe the handler [%v] should be register as 'POST' or 'PUT' method, now is %v.", handlerName, hw.Methods))
+			if !hasBodyMethod(hw) {
+				return nil, common.NewHTTPError(http.StatusMethodNotAllowed, fmt.Sprintf("Maybe the handler [%v] should be register as one of %v method, now is %v.", handlerName, bodyMethods, hw.Methods))
+			}
+
+			// 流式读取，不缓存请求体
+			switch param.RealType {
+			case typeReader, typeReadCloser:
+				args = append(args, reflect.ValueOf(request.Body).Convert(param.RealType))
+				continue
+			case typeJSONDecoder:
+				args = append(args, reflect.ValueOf(json.NewDecoder(request.Body)))
+				continue
 			}
 
 			// 获取到 requestBody
-			bs := util.RecycleRequestBody(request)
-			if bs != nil {
-				var arg interface{}
-				// switch 即使两个 case 条件相同，也不能合并，必须分开
-				switch param.RealType.Kind() {
-				case reflect.Map:
-					arg = reflect.MakeMap(param.ElemType).Interface()
-					break
-				case reflect.Slice:
-					arg = reflect.MakeSlice(param.ElemType, 0, 0).Interface()
-					break
-				case reflect.Array:
-					arg = reflect.New(reflect.ArrayOf(0, param.ElemType))
-					break
-				case reflect.Struct:
-					arg = reflect.New(param.ElemType).Interface()
-					break
-				case reflect.Ptr:
-					arg = reflect.New(param.ElemType).Interface()
-					break
+			bs, err := util.ReadRequestBody(request)
+			if err != nil {
+				return nil, bodyError(err)
+			}
+			if len(bs) > 0 {
+				// 按 Content-Type 选择解码器，未指定时默认为 json
+				mediaType := codec.MediaTypeJSON
+				if contentType := request.Header.Get("Content-Type"); contentType != "" {
+					mediaType = codec.Normalize(contentType)
+				}
+				decoder := codec.GetDecoder(mediaType)
+				if decoder == nil {
+					return nil, common.NewHTTPError(http.StatusUnsupportedMediaType, fmt.Sprintf("The media type [%v] of request body is not supported.", mediaType))
 				}
 
-				// json 解码
-				err := json.Unmarshal(bs, arg)
-				if err != nil {
-					gog.Error(err)
-					continue
+				// 统一解码到指针中
+				arg := reflect.New(param.ElemType)
+				if err := decoder.Decode(bytes.NewReader(bs), arg.Interface()); err != nil {
+					return nil, common.WrapHTTPError(http.StatusBadRequest, fmt.Errorf("Can not decode request body as [%v]: %v", mediaType, err))
 				}
 
-				val := reflect.ValueOf(arg)
-				// 如果接收的是 struct 类型，需要从指针中获取到 struct
-				if param.RealType.Kind() == reflect.Struct {
-					val = reflect.ValueOf(arg).Elem()
+				val := arg
+				// 如果接收的不是指针，需要从指针中获取到具体值
+				if !param.IsPtr {
+					val = arg.Elem()
+				}
+				if ex := validate(param, val); ex != nil {
+					return nil, ex
 				}
 				// 添加到参数列表
 				args = append(args, val)
@@ -347,7 +675,7 @@ func (rd *RequestDispatcher) resolve(hw *wire.HandlerWire, writer http.ResponseW
 		if isMultipart, multi, isPtr := isMultipartFile(param); isMultipart {
 			files, headers, err := util.FormFiles(request, param.Name)
 			if err != nil {
-				return nil, common.NewHTTPError(http.StatusBadRequest, err.Error())
+				return nil, bodyError(err)
 			}
 
 			var val reflect.Value
@@ -399,15 +727,17 @@ func (rd *RequestDispatcher) resolve(hw *wire.HandlerWire, writer http.ResponseW
 			temp := reflect.New(param.ElemType)
 
 			// 装配VO模型
-			temp, ex := getVOParam(request, temp.Interface())
-			if ex != nil {
-				return nil, ex
+			if err := binder.Bind(request, variables, temp.Interface()); err != nil {
+				return nil, bodyError(err)
 			}
 			// 添加到参数列表
 			// 如果接收的是 struct 类型，需要从指针中获取到 struct
 			if param.RealType.Kind() == reflect.Struct {
 				temp = temp.Elem()
 			}
+			if ex := validate(param, temp); ex != nil {
+				return nil, ex
+			}
 			args = append(args, temp)
 			continue
 		}
@@ -420,12 +750,132 @@ func (rd *RequestDispatcher) resolve(hw *wire.HandlerWire, writer http.ResponseW
 		if temp == "" && param.Required {
 			return nil, common.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The param [%v] is required, but no value received.", param.Name))
 		}
+		val, ex := convertParam(param, temp)
+		if ex != nil {
+			return nil, ex
+		}
+		if ex := validate(param, val); ex != nil {
+			return nil, ex
+		}
 		// 添加到参数列表
-		args = append(args, util.StringToValue(param.RealType.Kind(), temp))
+		args = append(args, val)
 	}
 	return args, nil
 }
 
+// limitBody 限制请求体大小
+//
+// 路由上的配置优先于全局配置，Content-Length 已超过限制时直接为 413
+func (rd *RequestDispatcher) limitBody(hw *wire.HandlerWire, request *http.Request) *common.HTTPError {
+	limit := hw.MaxBodySize
+	if limit <= 0 {
+		limit = rd.context.GetMaxBodySize()
+	}
+	if limit <= 0 {
+		return nil
+	}
+	if request.ContentLength > limit {
+		return common.NewHTTPError(http.StatusRequestEntityTooLarge, fmt.Sprintf("The request body is larger than %d bytes.", limit))
+	}
+	util.LimitRequestBody(request, limit)
+	return nil
+}
+
+// errorStatus 错误对应的状态码
+//
+// 错误链中有 *common.HTTPError 时为其状态码，否则为 def
+func errorStatus(err error, def int) int {
+	var he *common.HTTPError
+	if errors.As(err, &he) && he.Status > 0 {
+		return he.Status
+	}
+	return def
+}
+
+// bodyError 读取请求体时的错误
+//
+// 超过限制的大小时为 413，其他为 400
+func bodyError(err error) *common.HTTPError {
+	if errors.Is(err, util.ErrBodyTooLarge) {
+		return common.WrapHTTPError(http.StatusRequestEntityTooLarge, err)
+	}
+	return common.WrapHTTPError(http.StatusBadRequest, err)
+}
+
+// canCarryBody 请求方法是否可以携带请求体
+func canCarryBody(method string) bool {
+	for _, md := range bodyMethods {
+		if string(md) == method {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBodyMethod 处理器是否支持可以携带请求体的请求方法
+func hasBodyMethod(hw *wire.HandlerWire) bool {
+	for _, md := range bodyMethods {
+		if VerifyMethod(hw, string(md)) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConsumes 检查请求体的媒体类型
+//
+// 有请求体但 Content-Type 不在 Consumes 中时为 415
+func checkConsumes(hw *wire.HandlerWire, request *http.Request) *common.HTTPError {
+	if len(hw.Consumes) == 0 || request.ContentLength == 0 && request.Header.Get("Content-Type") == "" {
+		return nil
+	}
+	mediaType := codec.Normalize(request.Header.Get("Content-Type"))
+	for _, consume := range hw.Consumes {
+		if codec.Normalize(consume) == mediaType {
+			return nil
+		}
+	}
+	return common.NewHTTPError(http.StatusUnsupportedMediaType, fmt.Sprintf("The media type [%v] of request body is not supported, supported are %v.", mediaType, hw.Consumes))
+}
+
+// convertParam 将参数值转换为参数类型
+//
+// 未传值时为零值，转换失败时为 400，并说明参数名、期望的类型和接收到的值
+func convertParam(param *common.Param, value string) (reflect.Value, *common.HTTPError) {
+	if value == "" {
+		return reflect.Zero(param.RealType), nil
+	}
+	val, err := util.ConvertValue(param.RealType, value)
+	if err != nil {
+		if ce, ok := err.(*util.ConversionError); ok {
+			ce.Name = param.Name
+		}
+		return reflect.Value{}, common.WrapHTTPError(http.StatusBadRequest, err)
+	}
+	return val, nil
+}
+
+// validate 校验参数值
+//
+// 结构体参数按 validate 标签校验，再按 Ship 上配置的规则校验
+// 校验不通过时为 400，并携带所有字段错误
+func validate(param *common.Param, value reflect.Value) *common.HTTPError {
+	var err error
+	if param.ElemType.Kind() == reflect.Struct && value.CanInterface() {
+		err = validator.Validate(value.Interface())
+	}
+	if err == nil && len(param.Validations) > 0 {
+		err = validator.Var(param.Name, value.Interface(), param.Validations...)
+	}
+	if err == nil {
+		return nil
+	}
+	if errs, ok := err.(validator.Errors); ok {
+		return common.WrapHTTPError(http.StatusBadRequest, errs).WithExtension("errors", []*validator.FieldError(errs))
+	}
+	return common.WrapHTTPError(http.StatusInternalServerError, err)
+}
+
 // 是否是文件上传
 //
 // 返回值： 是否是文件上传，是否有多个文件，是否是文件指针
@@ -459,60 +909,63 @@ func isMultipartFile(param *common.Param) (isMultipart, multi, isPtr bool) {
 	return
 }
 
-// getVOParam 自动填充 VO 参数
-func getVOParam(request *http.Request, value interface{}) (reflect.Value, *common.HTTPError) {
-	realValue := reflect.ValueOf(value)
-	// 临时变量，便于从指针转换为 struct
-	elemValue := realValue
-	if elemValue.Kind() == reflect.Ptr {
-		elemValue = elemValue.Elem()
+// resolveParam 使用自定义的参数处理器处理参数
+func resolveParam(pr resolver.ParamResolver, param *common.Param, writer http.ResponseWriter, request *http.Request) (reflect.Value, *common.HTTPError) {
+	val, err := pr.Resolve(param, writer, request)
+	if err != nil {
+		var he *common.HTTPError
+		if errors.As(err, &he) {
+			return reflect.Value{}, he
+		}
+		return reflect.Value{}, common.WrapHTTPError(http.StatusBadRequest, err)
 	}
-	elemType := elemValue.Type()
-	count := elemType.NumField()
+	if !val.IsValid() {
+		return reflect.Zero(param.RealType), nil
+	}
+	if !val.Type().AssignableTo(param.RealType) {
+		return reflect.Value{}, common.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("The param resolver [%T] resolved [%v] as [%v], but [%v] is required.", pr, param.Name, val.Type(), param.RealType))
+	}
+	return val, nil
+}
 
-	for i := 0; i < count; i++ {
-		field := elemType.Field(i)
-		val, ex := getVOParamValueByField(request, field)
-		if ex != nil {
-			return val, ex
+// cookieParam 从 cookie 中获取参数
+//
+// 接收类型为 http.Cookie 时使用整个 cookie，否则转换 cookie 值
+func cookieParam(param *common.Param, request *http.Request) (reflect.Value, *common.HTTPError) {
+	cookie, err := request.Cookie(param.Name)
+	if err != nil {
+		if param.Required {
+			return reflect.Value{}, common.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The cookie [%v] is required, but not found.", param.Name))
 		}
-		if realValue.Kind() == reflect.Ptr {
-			// 如果 对象 是 指针，则可以直接设置字段值
-			util.FieldSet(elemValue.Field(i), val)
-		} else {
-			// 否则需要到指针指向的对象，再设置值
-			util.FieldSet(elemValue.Elem().Field(i), val)
+		return reflect.Zero(param.RealType), nil
+	}
+	if param.ElemType == typeCookie {
+		if param.IsPtr {
+			return reflect.ValueOf(cookie), nil
 		}
+		return reflect.ValueOf(*cookie), nil
 	}
-	// 返回原始 数据
-	return realValue, nil
-}
-
-// getVOParamValueByField 填充 VO 字段
-func getVOParamValueByField(request *http.Request, field reflect.StructField) (reflect.Value, *common.HTTPError) {
-	name := field.Tag.Get("param")
-	if name == "" {
-		name = util.FirstToLower(field.Name)
+	val, ex := convertParam(param, cookie.Value)
+	if ex != nil {
+		return reflect.Value{}, ex
 	}
-	_, inHeader := field.Tag.Lookup("header")
-	_, required := field.Tag.Lookup("required")
+	if ex := validate(param, val); ex != nil {
+		return reflect.Value{}, ex
+	}
+	return val, nil
+}
 
-	var temp string
-	if inHeader {
-		temp = getHeaderParam(request, name)
-		if temp == "" {
-			temp = getHeaderParam(request, field.Name)
-		}
-	} else {
-		temp = getNormalParam(request, name)
-		if temp == "" {
-			temp = getNormalParam(request, field.Name)
-		}
+// attribute 从 request 属性中获取参数，属性不存在时为零值
+func attribute(param *common.Param, request *http.Request) (reflect.Value, *common.HTTPError) {
+	value := util.GetRequestAttribute(request, common.AttributeKey(param.Name))
+	if value == nil {
+		return reflect.Zero(param.RealType), nil
 	}
-	if temp == "" && required {
-		return reflect.ValueOf(nil), common.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("The param [%v] is required, but no value received.", name))
+	val := reflect.ValueOf(value)
+	if !val.Type().AssignableTo(param.RealType) {
+		return reflect.Value{}, common.NewHTTPError(http.StatusInternalServerError, fmt.Sprintf("The attribute [%v] of type [%v] can not be used as [%v].", param.Name, val.Type(), param.RealType))
 	}
-	return util.StringToValue(field.Type.Kind(), temp), nil
+	return val, nil
 }
 
 // getHeaderParam 从请求头中获取参数
@@ -543,12 +996,16 @@ func GetPathVariableValue(index int, path string) string {
 	return ""
 }
 
+// joinMethods 拼接请求方法，用于 Allow 响应头
+func joinMethods(methods []common.Method) string {
+	temp := make([]string, 0, len(methods))
+	for _, md := range methods {
+		temp = append(temp, string(md))
+	}
+	return strings.Join(temp, ", ")
+}
+
 // VerifyMethod 校验请求方法
 func VerifyMethod(hw *wire.HandlerWire, method string) bool {
-	for _, md := range hw.Methods {
-		if string(md) == method {
-			return true
-		}
-	}
-	return false
+	return hw.Supports(method)
 }
@@ -0,0 +1,118 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 2:36 下午
+// version: 1.0.0
+// desc   : 分发过程中使用的响应器
+
+package dispatcher
+
+import (
+	"net/http"
+	"sync"
+)
+
+// headResponseWriter HEAD 请求响应器
+//
+// 只响应 header，丢弃所有 body
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+// newHeadResponseWriter 创建 HEAD 请求响应器
+func newHeadResponseWriter(writer http.ResponseWriter) *headResponseWriter {
+	return &headResponseWriter{ResponseWriter: writer}
+}
+
+// Write 丢弃 body
+func (hw *headResponseWriter) Write(bs []byte) (int, error) {
+	return len(bs), nil
+}
+
+// timeoutWriter 超时响应器
+//
+// 超时前写入的 header 和 body 正常响应，超时后丢弃处理器的所有写入
+type timeoutWriter struct {
+	http.ResponseWriter
+	mu          sync.Mutex
+	header      http.Header
+	wroteHeader bool
+	timedOut    bool
+}
+
+// newTimeoutWriter 创建超时响应器
+func newTimeoutWriter(writer http.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{ResponseWriter: writer, header: make(http.Header)}
+}
+
+// Header 获取 header，首次写入时才会复制到原响应器中
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+// WriteHeader 写入状态码，超时后丢弃
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeader(status)
+}
+
+// Write 写入 body，超时后返回 http.ErrHandlerTimeout
+func (tw *timeoutWriter) Write(bs []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeader(http.StatusOK)
+	return tw.ResponseWriter.Write(bs)
+}
+
+// Flush 刷新缓冲区
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeader(http.StatusOK)
+	if flusher, ok := tw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// writeHeader 复制 header 并写入状态码，只写入一次
+func (tw *timeoutWriter) writeHeader(status int) {
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	dst := tw.ResponseWriter.Header()
+	for k, v := range tw.header {
+		dst[k] = v
+	}
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+// timeout 标记为已超时
+//
+// 返回是否还能响应，即处理器尚未写入 header
+func (tw *timeoutWriter) timeout() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.timedOut = true
+	return !tw.wroteHeader
+}
@@ -21,37 +21,98 @@
 package filter
 
 import (
+	"fmt"
 	"net/http"
-	"strings"
 	"sync"
 
 	"github.com/yhyzgn/gox/ctx"
 
 	"github.com/yhyzgn/gog"
-	"github.com/yhyzgn/gox/common"
 	"github.com/yhyzgn/gox/component/dispatcher"
 	"github.com/yhyzgn/gox/util"
 )
 
+// maxMatchedCache 最多缓存的请求路径个数，超过后淘汰最久未使用的路径，避免路径变量撑大缓存
+//
+// 过滤器按实际的请求路径匹配，未映射的路径（静态资源、404 等）也要经过过滤器，
+// 并且同一路由的不同路径变量也可能匹配到不同的过滤器，所以不能按路由预先匹配
+const maxMatchedCache = 4096
+
 type item struct {
-	path   string
-	filter Filter
+	name      string
+	path      string
+	pattern   *util.AntPattern
+	condition *util.Condition
+	order     int
+	filter    Filter
+}
+
+// Info 过滤器信息
+type Info struct {
+	Name      string          // 名称，未命名时为过滤器的类型
+	Path      string          // 匹配的 path
+	Condition *util.Condition // path 之外的匹配条件，为 nil 时不限制
+	Order     int             // 执行顺序，越小越先执行
+	Filter    Filter          // 过滤器
 }
 
 // Chain 过滤器链
+//
+// 过滤器中接收到的是每个请求各自的链，调用其 DoFilter() 继续执行下一个过滤器
 type Chain struct {
 	filters    []item
 	excludes   sync.Map
+	patterns   util.AntPatterns // 已编译的排除路径
+	context    *ctx.GoXContext
 	dispatcher dispatcher.Dispatcher
+	matched    *util.LRU // 请求路径 -> 匹配到的过滤器
+	iterator   *Iterator // 每个请求的迭代器，只存在于传递给过滤器的链中
+}
+
+// Iterator 每个请求的过滤器迭代器
+//
+// 只包含 path 匹配当前请求的过滤器，依次执行满足匹配条件的过滤器后将请求交给分发器
+type Iterator struct {
+	chain *Chain // 所属的过滤器链
+	items []item // path 匹配当前请求的过滤器
+	index int    // 下一个过滤器的索引
+	view  Chain  // 传递给过滤器的链
+}
+
+// Next 执行下一个满足匹配条件的过滤器，所有过滤器执行完后将请求交给分发器
+//
+// 重复调用时，分发器也只会执行一次
+func (it *Iterator) Next(writer http.ResponseWriter, request *http.Request) {
+	for it.index < len(it.items) {
+		item := it.items[it.index]
+		it.index++
+		if item.condition.Matches(request) {
+			item.filter.DoFilter(writer, request, &it.view)
+			return
+		}
+	}
+	if it.index == len(it.items) {
+		it.index++
+		it.chain.dispatcher.Dispatch(writer, request)
+	}
 }
 
 // NewChain 一个新链
+//
+// 默认使用全局的上下文，仅适用于默认服务 gox.Default()，其他服务必须通过 SetContext 配置各自的上下文
 func NewChain() *Chain {
 	return &Chain{
 		filters: make([]item, 0),
+		context: ctx.C(),
+		matched: util.NewLRU(maxMatchedCache),
 	}
 }
 
+// SetContext 配置上下文
+func (fc *Chain) SetContext(context *ctx.GoXContext) {
+	fc.context = context
+}
+
 // SetDispatcher 设置请求分发器
 // 当所有过滤器执行完后，需要执行该分发器
 func (fc *Chain) SetDispatcher(dispatcher dispatcher.Dispatcher) {
@@ -59,17 +120,19 @@ func (fc *Chain) SetDispatcher(dispatcher dispatcher.Dispatcher) {
 }
 
 // AddFilters 向链中添加过滤器
-// 添加顺序 即 执行顺序
-// path 匹配方式：
+// 添加顺序 即 执行顺序，order 为 0，名称为过滤器的类型
+// path 为 Ant 风格路径，见 util.CompileAntPattern：
 // 				/		->		所有请求
 //				/xx		->		严格匹配
-//				/xx/*	->		前缀匹配
+//				/xx/*	->		一段前缀匹配
+//				/xx/**	->		多段前缀匹配
 func (fc *Chain) AddFilters(path string, filters ...Filter) *Chain {
 	if path == "" || filters == nil || len(filters) == 0 {
 		return fc
 	}
 	for _, flt := range filters {
-		fc.filters = append(fc.filters, item{
+		fc.insert(item{
+			name:   fmt.Sprintf("%T", flt),
 			path:   path,
 			filter: flt,
 		})
@@ -78,13 +141,169 @@ func (fc *Chain) AddFilters(path string, filters ...Filter) *Chain {
 	return fc
 }
 
+// AddFiltersWhen 向链中添加过滤器，path 和请求匹配条件都满足时才执行
+//
+// 如只在 POST 请求时执行：chain.AddFiltersWhen("/admin/**", util.When().Methods(http.MethodPost), filters...)
+func (fc *Chain) AddFiltersWhen(path string, condition *util.Condition, filters ...Filter) *Chain {
+	if path == "" || filters == nil || len(filters) == 0 {
+		return fc
+	}
+	for _, flt := range filters {
+		fc.insert(item{
+			name:      fmt.Sprintf("%T", flt),
+			path:      path,
+			condition: condition,
+			filter:    flt,
+		})
+	}
+	gog.DebugF("The Filters [%v] registered with condition.", path)
+	return fc
+}
+
+// Condition 为命名过滤器配置请求匹配条件
+func (fc *Chain) Condition(name string, condition *util.Condition) *Chain {
+	fc.filters[fc.mustIndexOf(name)].condition = condition
+	fc.resetMatched()
+	return fc
+}
+
+// AddFilter 添加命名过滤器
+//
+// order 越小越先执行，相同时按添加顺序执行，名称不能重复
+func (fc *Chain) AddFilter(name, path string, order int, filter Filter) *Chain {
+	fc.checkName(name)
+	fc.insert(item{
+		name:   name,
+		path:   path,
+		order:  order,
+		filter: filter,
+	})
+	gog.DebugF("The Filter [%v] of [%v] registered with order [%v].", name, path, order)
+	return fc
+}
+
+// Before 在命名过滤器 target 之前添加命名过滤器，order 与 target 相同
+func (fc *Chain) Before(target, name, path string, filter Filter) *Chain {
+	fc.checkName(name)
+	index := fc.mustIndexOf(target)
+	fc.insertAt(index, item{
+		name:   name,
+		path:   path,
+		order:  fc.filters[index].order,
+		filter: filter,
+	})
+	gog.DebugF("The Filter [%v] of [%v] registered before [%v].", name, path, target)
+	return fc
+}
+
+// After 在命名过滤器 target 之后添加命名过滤器，order 与 target 相同
+func (fc *Chain) After(target, name, path string, filter Filter) *Chain {
+	fc.checkName(name)
+	index := fc.mustIndexOf(target)
+	fc.insertAt(index+1, item{
+		name:   name,
+		path:   path,
+		order:  fc.filters[index].order,
+		filter: filter,
+	})
+	gog.DebugF("The Filter [%v] of [%v] registered after [%v].", name, path, target)
+	return fc
+}
+
+// Remove 移除该名称的所有过滤器
+func (fc *Chain) Remove(name string) *Chain {
+	filters := fc.filters[:0]
+	for _, item := range fc.filters {
+		if item.name != name {
+			filters = append(filters, item)
+		}
+	}
+	fc.filters = filters
+	fc.resetMatched()
+	return fc
+}
+
+// Replace 替换命名过滤器，path 和 order 不变
+func (fc *Chain) Replace(name string, filter Filter) *Chain {
+	fc.filters[fc.mustIndexOf(name)].filter = filter
+	fc.resetMatched()
+	return fc
+}
+
+// Filters 按执行顺序获取所有过滤器
+func (fc *Chain) Filters() []Info {
+	infos := make([]Info, 0, len(fc.filters))
+	for _, item := range fc.filters {
+		infos = append(infos, Info{
+			Name:      item.name,
+			Path:      item.path,
+			Condition: item.condition,
+			Order:     item.order,
+			Filter:    item.filter,
+		})
+	}
+	return infos
+}
+
+// insert 按 order 添加，相同时排在已有过滤器之后
+func (fc *Chain) insert(it item) {
+	index := len(fc.filters)
+	for i, exist := range fc.filters {
+		if exist.order > it.order {
+			index = i
+			break
+		}
+	}
+	fc.insertAt(index, it)
+}
+
+// insertAt 添加到指定位置
+func (fc *Chain) insertAt(index int, it item) {
+	it.pattern = compilePattern(it.path)
+	fc.filters = append(fc.filters, item{})
+	copy(fc.filters[index+1:], fc.filters[index:])
+	fc.filters[index] = it
+	fc.resetMatched()
+}
+
+// checkName 检查名称是否为空或者重复
+func (fc *Chain) checkName(name string) {
+	if name == "" {
+		gog.Fatal("The name of filter can not be empty.")
+	}
+	if fc.indexOf(name) >= 0 {
+		gog.FatalF("The filter named [%v] has been registered.", name)
+	}
+}
+
+// indexOf 获取命名过滤器的位置，不存在时为 -1
+func (fc *Chain) indexOf(name string) int {
+	for i, item := range fc.filters {
+		if item.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// mustIndexOf 获取命名过滤器的位置，不存在时终止
+func (fc *Chain) mustIndexOf(name string) int {
+	index := fc.indexOf(name)
+	if index < 0 {
+		gog.FatalF("The filter named [%v] not found.", name)
+	}
+	return index
+}
+
 // Exclude 添加排除路径
 //
-// 支持 前缀匹配 & 严格匹配
+// 支持 Ant 风格路径，排除路径至少与过滤器的 path 一样具体时才排除该过滤器，见 util.AntPattern.Compare
 func (fc *Chain) Exclude(path string) *Chain {
 	if _, ok := fc.excludes.Load(path); !ok {
 		fc.excludes.Store(path, true)
+		fc.patterns = append(fc.patterns, compilePattern(path))
 	}
+	fc.resetMatched()
 	return fc
 }
 
@@ -100,51 +319,62 @@ func (fc *Chain) GetExcludes() map[string]bool {
 
 // DoFilter 逐个执行过滤器
 // 执行顺序 为 添加顺序
+//
+// 在过滤器中调用时，继续执行当前请求的下一个过滤器
 func (fc *Chain) DoFilter(writer http.ResponseWriter, request *http.Request) {
-	// 匹配时忽略ContextPath
-	reqPath := strings.ReplaceAll(request.URL.Path, ctx.C().GetContextPath(), "")
-	// 先判断这些请求是否已经被排除在 过滤器 外
-	if util.IsExcludedRequest(reqPath, fc.GetExcludes()) {
-		gog.TraceF("The request [%v] has been excluded", request.URL.Path)
-		fc.dispatcher.Dispatch(writer, request)
+	if fc.iterator != nil {
+		fc.iterator.Next(writer, request)
 		return
 	}
+	fc.Iterator(request).Next(writer, request)
+}
+
+// Iterator 为请求创建新的迭代器
+func (fc *Chain) Iterator(request *http.Request) *Iterator {
+	it := &Iterator{
+		chain: fc,
+		items: fc.match(request),
+	}
+	it.view.iterator = it
+	return it
+}
 
-	// 计算索引
-	request, index := fc.getIndexAndIncrement(request)
+// match 获取 path 匹配请求的所有过滤器
+func (fc *Chain) match(request *http.Request) []item {
+	// 匹配时忽略ContextPath
+	reqPath := util.StripContextPath(request.URL.Path, fc.context.GetContextPath())
+	if items, ok := fc.matched.Get(reqPath); ok {
+		return items.([]item)
+	}
 
-	// 走完所有过滤器，需要将请求交给 dispatcher
-	if index == len(fc.filters) {
-		fc.dispatcher.Dispatch(writer, request)
-		return
+	var items []item
+	// 匹配的最具体的排除路径，比它更具体的过滤器 path 才不会被排除
+	exclude := fc.patterns.Best(reqPath)
+	for _, item := range fc.filters {
+		if !item.pattern.Match(reqPath) {
+			continue
+		}
+		if exclude != nil && exclude.Compare(item.pattern) <= 0 {
+			gog.TraceF("The request [%v] has been excluded from filter [%v] by [%v]", request.URL.Path, item.name, exclude)
+			continue
+		}
+		items = append(items, item)
 	}
 
-	item := fc.filters[index]
-
-	// 匹配 path，未匹配到的 filter 直接跳过
-	if item.path == "/" {
-		// 所有请求
-		gog.TraceF("The request [%v] has passed by filter [/]", request.URL.Path)
-		item.filter.DoFilter(writer, request, fc)
-	} else if item.path == reqPath {
-		// 严格匹配，只有路径完全相同才走过滤器
-		gog.TraceF("The request [%v] has passed by filter [%v]", request.URL.Path, item.path)
-		item.filter.DoFilter(writer, request, fc)
-	} else if util.MatchedRequestByPathPattern(reqPath, item.path) {
-		// 前缀|后缀匹配成功，走过滤器
-		gog.TraceF("The request [%v] has passed by filter [%v]", request.URL.Path, item.path)
-		item.filter.DoFilter(writer, request, fc)
-	} else {
-		gog.TraceF("The request [%v] has skipped by filter [%v]", request.URL.Path, item.path)
-		// 匹配不到过滤器，则递归回当前链，继续下一次匹配
-		fc.DoFilter(writer, request)
-	}
-}
-
-// getIndexAndIncrement 获取当前索引，并递增
-func (fc *Chain) getIndexAndIncrement(req *http.Request) (*http.Request, int) {
-	// 获取到当前请求中的过滤器 索引
-	index := util.GetRequestAttribute(req, common.RequestFilterIndexName).(int)
-	// 实时更新 索引
-	return util.SetRequestAttribute(req, common.RequestFilterIndexName, index+1), index
+	fc.matched.Add(reqPath, items)
+	return items
+}
+
+// resetMatched 过滤器或者排除路径变化后，清空已匹配的缓存
+func (fc *Chain) resetMatched() {
+	fc.matched.Purge()
+}
+
+// compilePattern 编译 Ant 风格路径，失败时终止
+func compilePattern(path string) *util.AntPattern {
+	pattern, err := util.CompileAntPattern(path)
+	if err != nil {
+		gog.Fatal(err)
+	}
+	return pattern
 }
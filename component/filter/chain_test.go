@@ -0,0 +1,237 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 3:30 下午
+// version: 1.0.0
+// desc   : 过滤器链测试
+
+package filter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/yhyzgn/gox/util"
+)
+
+type recordFilter struct {
+	name    string
+	records *[]string
+}
+
+func (rf recordFilter) DoFilter(writer http.ResponseWriter, request *http.Request, chain *Chain) {
+	*rf.records = append(*rf.records, rf.name)
+	// 替换为不带任何属性的请求，也能继续执行
+	chain.DoFilter(writer, request.WithContext(context.Background()))
+}
+
+type recordDispatcher struct {
+	records *[]string
+}
+
+func (rd recordDispatcher) Dispatch(writer http.ResponseWriter, request *http.Request) {
+	*rd.records = append(*rd.records, "dispatch")
+}
+
+type noopFilter struct {
+}
+
+func (noopFilter) DoFilter(writer http.ResponseWriter, request *http.Request, chain *Chain) {
+	chain.DoFilter(writer, request)
+}
+
+type noopDispatcher struct {
+}
+
+func (noopDispatcher) Dispatch(writer http.ResponseWriter, request *http.Request) {
+}
+
+func TestChain_DoFilter(t *testing.T) {
+	records := make([]string, 0)
+	chain := NewChain()
+	chain.SetDispatcher(recordDispatcher{records: &records})
+	chain.AddFilters("/", recordFilter{name: "all", records: &records})
+	chain.AddFilters("/api/*", recordFilter{name: "api", records: &records})
+	chain.AddFilters("/admin", recordFilter{name: "admin", records: &records})
+	chain.AddFilters("/api/user", recordFilter{name: "user", records: &records})
+	chain.Exclude("/static/*")
+
+	cases := []struct {
+		path    string
+		records []string
+	}{
+		{"/api/user", []string{"all", "api", "user", "dispatch"}},
+		{"/admin", []string{"all", "admin", "dispatch"}},
+		{"/api/user", []string{"all", "api", "user", "dispatch"}},
+		{"/static/app.js", []string{"dispatch"}},
+	}
+	for _, c := range cases {
+		records = records[:0]
+		chain.DoFilter(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, c.path, nil))
+		if !reflect.DeepEqual(records, c.records) {
+			t.Errorf("[%v] should pass by %v, but %v", c.path, c.records, records)
+		}
+	}
+}
+
+func TestChain_Exclude(t *testing.T) {
+	records := make([]string, 0)
+	chain := NewChain()
+	chain.SetDispatcher(recordDispatcher{records: &records})
+	chain.AddFilters("/", recordFilter{name: "all", records: &records})
+	chain.AddFilters("/api/**", recordFilter{name: "api", records: &records})
+	chain.AddFilters("/api/public/login", recordFilter{name: "login", records: &records})
+	chain.AddFilters("/admin/**", recordFilter{name: "admin", records: &records})
+	chain.Exclude("/api/public/**").Exclude("/admin/**")
+
+	// 排除路径至少与过滤器的 path 一样具体时才排除该过滤器
+	cases := []struct {
+		path    string
+		records []string
+	}{
+		{"/api/user", []string{"all", "api", "dispatch"}},
+		{"/api/public/help", []string{"dispatch"}},
+		{"/api/public/login", []string{"login", "dispatch"}},
+		{"/admin/users", []string{"dispatch"}},
+	}
+	for _, c := range cases {
+		records = records[:0]
+		chain.DoFilter(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, c.path, nil))
+		if !reflect.DeepEqual(records, c.records) {
+			t.Errorf("[%v] should pass by %v, but %v", c.path, c.records, records)
+		}
+	}
+}
+
+func TestChain_MatchedCache(t *testing.T) {
+	records := make([]string, 0)
+	chain := NewChain()
+	chain.SetDispatcher(recordDispatcher{records: &records})
+	chain.AddFilters("/users/*", recordFilter{name: "user", records: &records})
+
+	// 路径变量不会让缓存无限增长
+	for i := 0; i < maxMatchedCache+100; i++ {
+		chain.DoFilter(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/%d", i), nil))
+	}
+	if chain.matched.Len() != maxMatchedCache {
+		t.Errorf("should cache %d paths, but %d", maxMatchedCache, chain.matched.Len())
+	}
+
+	records = records[:0]
+	chain.DoFilter(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/0", nil))
+	if !reflect.DeepEqual(records, []string{"user", "dispatch"}) {
+		t.Errorf("evicted path should be matched again, but %v", records)
+	}
+}
+
+func TestChain_AddFiltersWhen(t *testing.T) {
+	records := make([]string, 0)
+	chain := NewChain()
+	chain.SetDispatcher(recordDispatcher{records: &records})
+	chain.AddFiltersWhen("/admin/**", util.When().Methods(http.MethodPost), recordFilter{name: "csrf", records: &records})
+	chain.AddFilter("host", "/", 0, recordFilter{name: "host", records: &records})
+	chain.Condition("host", util.When().Hosts("*.gox.io"))
+
+	cases := []struct {
+		method  string
+		url     string
+		records []string
+	}{
+		{http.MethodPost, "http://api.gox.io/admin/user", []string{"csrf", "host", "dispatch"}},
+		{http.MethodGet, "http://api.gox.io/admin/user", []string{"host", "dispatch"}},
+		{http.MethodPost, "http://localhost/admin/user", []string{"csrf", "dispatch"}},
+		{http.MethodGet, "http://localhost/admin/user", []string{"dispatch"}},
+	}
+	for _, c := range cases {
+		records = records[:0]
+		chain.DoFilter(httptest.NewRecorder(), httptest.NewRequest(c.method, c.url, nil))
+		if !reflect.DeepEqual(records, c.records) {
+			t.Errorf("[%v %v] should pass by %v, but %v", c.method, c.url, c.records, records)
+		}
+	}
+}
+
+func BenchmarkChain_DoFilter(b *testing.B) {
+	chain := NewChain()
+	chain.SetDispatcher(noopDispatcher{})
+	for i := 0; i < 10; i++ {
+		chain.AddFilters(fmt.Sprintf("/api/v%d/*", i%2), noopFilter{})
+	}
+	writer := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/user", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		chain.DoFilter(writer, request)
+	}
+}
+
+func BenchmarkChain_DoFilterParallel(b *testing.B) {
+	chain := NewChain()
+	chain.SetDispatcher(noopDispatcher{})
+	for i := 0; i < 10; i++ {
+		chain.AddFilters(fmt.Sprintf("/api/v%d/*", i%2), noopFilter{})
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		writer := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/user", nil)
+		for pb.Next() {
+			chain.DoFilter(writer, request)
+		}
+	})
+}
+
+func TestChain_Filters(t *testing.T) {
+	records := make([]string, 0)
+	filter := func(name string) Filter {
+		return recordFilter{name: name, records: &records}
+	}
+
+	chain := NewChain()
+	chain.SetDispatcher(recordDispatcher{records: &records})
+	chain.AddFilter("auth", "/", 10, filter("auth"))
+	chain.AddFilter("cors", "/", -10, filter("cors"))
+	chain.AddFilters("/", filter("plain"))
+	chain.Before("auth", "trace", "/", filter("trace"))
+	chain.After("cors", "gzip", "/api/*", filter("gzip"))
+	chain.AddFilter("log", "/", 10, filter("log"))
+	chain.Replace("log", filter("access"))
+	chain.AddFilter("tmp", "/", 0, filter("tmp"))
+	chain.Remove("tmp")
+
+	names := make([]string, 0)
+	for _, info := range chain.Filters() {
+		names = append(names, fmt.Sprintf("%v:%d", info.Name, info.Order))
+	}
+	expected := []string{"cors:-10", "gzip:-10", "filter.recordFilter:0", "trace:10", "auth:10", "log:10"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("filters should be %v, but %v", expected, names)
+	}
+
+	chain.DoFilter(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/user", nil))
+	expected = []string{"cors", "plain", "trace", "auth", "access", "dispatch"}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("[/user] should pass by %v, but %v", expected, records)
+	}
+}
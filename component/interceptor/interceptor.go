@@ -35,6 +35,25 @@ type Interceptor interface {
 	// 返回 true 将继续往下执行，返回 false 则截断请求
 	PreHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler) (bool, *http.Request, http.ResponseWriter)
 
-	// 请求处理后
+	// 请求处理后，响应之前
+	// 处理器返回错误时也会执行
 	AfterHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler, result reflect.Value, err error) (*http.Request, http.ResponseWriter)
 }
+
+// PostInterceptor 可以修改处理结果的拦截器
+//
+// 拦截器可选实现该接口，在 AfterHandle() 之前执行
+type PostInterceptor interface {
+	// PostHandle 请求处理后，响应之前
+	// 返回新的处理结果和错误，错误不为 nil 时交给异常处理器
+	PostHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler, result reflect.Value, err error) (reflect.Value, error)
+}
+
+// CompletionInterceptor 请求完成后执行的拦截器
+//
+// 拦截器可选实现该接口，只有 PreHandle() 通过的拦截器才会执行
+type CompletionInterceptor interface {
+	// AfterCompletion 请求完成后，无论成功、失败、被之后的拦截器截断或者 panic 都会执行
+	// err 为处理过程中的错误，panic 时为 *common.PanicError
+	AfterCompletion(writer http.ResponseWriter, request *http.Request, handler common.Handler, err error)
+}
@@ -21,12 +21,17 @@
 package interceptor
 
 import (
-	"github.com/yhyzgn/gog"
+	"net/http"
 	"sync"
+
+	"github.com/yhyzgn/gog"
+	"github.com/yhyzgn/gox/util"
 )
 
 type item struct {
 	path        string
+	pattern     *util.AntPattern
+	condition   *util.Condition
 	interceptor Interceptor
 }
 
@@ -34,6 +39,7 @@ type item struct {
 type Register struct {
 	interceptors []item
 	excludes     sync.Map
+	patterns     util.AntPatterns // 已编译的排除路径
 }
 
 // NewRegister 新的注册器
@@ -45,17 +51,20 @@ func NewRegister() *Register {
 
 // AddInterceptors 添加拦截器
 // 添加顺序 即 执行顺序
-// path 匹配方式：
+// path 为 Ant 风格路径，见 util.CompileAntPattern：
 // 				/		->		所有请求
 //				/xx		->		严格匹配
-//				/xx/*	->		前缀匹配
+//				/xx/*	->		一段前缀匹配
+//				/xx/**	->		多段前缀匹配
 func (ir *Register) AddInterceptors(path string, interceptors ...Interceptor) *Register {
 	if path == "" || interceptors == nil || len(interceptors) == 0 {
 		return ir
 	}
+	pattern := compilePattern(path)
 	for _, ipt := range interceptors {
 		ir.interceptors = append(ir.interceptors, item{
 			path:        path,
+			pattern:     pattern,
 			interceptor: ipt,
 		})
 	}
@@ -63,12 +72,33 @@ func (ir *Register) AddInterceptors(path string, interceptors ...Interceptor) *R
 	return ir
 }
 
+// AddInterceptorsWhen 添加拦截器，path 和请求匹配条件都满足时才执行
+//
+// 如只对非安全方法执行：register.AddInterceptorsWhen("/admin/**", util.When().Methods(http.MethodPost, http.MethodPut, http.MethodDelete), csrf)
+func (ir *Register) AddInterceptorsWhen(path string, condition *util.Condition, interceptors ...Interceptor) *Register {
+	if path == "" || interceptors == nil || len(interceptors) == 0 {
+		return ir
+	}
+	pattern := compilePattern(path)
+	for _, ipt := range interceptors {
+		ir.interceptors = append(ir.interceptors, item{
+			path:        path,
+			pattern:     pattern,
+			condition:   condition,
+			interceptor: ipt,
+		})
+	}
+	gog.InfoF("The Interceptor [%v] registered with condition.", path)
+	return ir
+}
+
 // Exclude 添加排除路径
 //
-// 支持 前缀匹配 & 严格匹配
+// 支持 Ant 风格路径，排除路径至少与拦截器的 path 一样具体时才排除该拦截器，见 util.AntPattern.Compare
 func (ir *Register) Exclude(path string) *Register {
 	if _, ok := ir.excludes.Load(path); !ok {
 		ir.excludes.Store(path, true)
+		ir.patterns = append(ir.patterns, compilePattern(path))
 	}
 	return ir
 }
@@ -83,6 +113,34 @@ func (ir *Register) GetExcludes() map[string]bool {
 	return excludes
 }
 
+// Matched 获取与请求路径匹配的所有拦截器
+//
+// 按添加顺序返回，被排除的拦截器不返回，不判断请求匹配条件
+func (ir *Register) Matched(path string) []Interceptor {
+	return ir.MatchedRequest(nil, path)
+}
+
+// MatchedRequest 获取与请求路径和请求匹配条件都匹配的所有拦截器
+//
+// 按添加顺序返回，被排除的拦截器不返回，request 为 nil 时不判断请求匹配条件
+func (ir *Register) MatchedRequest(request *http.Request, path string) []Interceptor {
+	matched := make([]Interceptor, 0)
+	// 匹配的最具体的排除路径，比它更具体的拦截器 path 才不会被排除
+	exclude := ir.patterns.Best(path)
+	for _, item := range ir.interceptors {
+		if item.pattern.Match(path) && exclude != nil && exclude.Compare(item.pattern) <= 0 {
+			gog.TraceF("The request [%v] has been excluded from interceptor [%v] by [%v].", path, item.path, exclude)
+			continue
+		}
+		if item.pattern.Match(path) && (request == nil || item.condition.Matches(request)) {
+			matched = append(matched, item.interceptor)
+		} else {
+			gog.TraceF("The request [%v] has skipped by interceptor [%v].", path, item.path)
+		}
+	}
+	return matched
+}
+
 // Iterate 遍历所有拦截器，并执行相应回到操作
 func (ir *Register) Iterate(iterator func(index int, path string, interceptor Interceptor) (skip, passed bool)) (bool, string) {
 	if iterator != nil {
@@ -113,3 +171,12 @@ func (ir *Register) ReverseIterate(iterator func(index int, path string, interce
 		}
 	}
 }
+
+// compilePattern 编译 Ant 风格路径，失败时终止
+func compilePattern(path string) *util.AntPattern {
+	pattern, err := util.CompileAntPattern(path)
+	if err != nil {
+		gog.Fatal(err)
+	}
+	return pattern
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 5:32 下午
+// version: 1.0.0
+// desc   : 校验错误
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldError 字段校验错误
+type FieldError struct {
+	Field   string      `json:"field"`           // 参数名，嵌套字段以 . 连接
+	Rule    string      `json:"rule"`            // 未通过的规则
+	Param   string      `json:"param,omitempty"` // 规则参数
+	Value   interface{} `json:"value,omitempty"` // 接收到的值
+	Message string      `json:"message"`         // 错误描述
+}
+
+// Errors 校验错误列表
+type Errors []*FieldError
+
+// newFieldError 创建字段校验错误
+func newFieldError(field string, rule Rule, value reflect.Value) *FieldError {
+	message := fmt.Sprintf("failed on rule [%v]", rule.Name)
+	if format, ok := messages[rule.Name]; ok {
+		if strings.Contains(format, "%v") {
+			message = fmt.Sprintf(format, strings.ReplaceAll(rule.Param, "|", ", "))
+		} else {
+			message = format
+		}
+	}
+
+	fe := &FieldError{
+		Field:   field,
+		Rule:    rule.Name,
+		Param:   rule.Param,
+		Message: message,
+	}
+	if value.IsValid() && value.CanInterface() {
+		fe.Value = value.Interface()
+	}
+	return fe
+}
+
+// Error 错误信息
+func (fe *FieldError) Error() string {
+	return fmt.Sprintf("the param [%v] %v", fe.Field, fe.Message)
+}
+
+// Error 错误信息，多个错误以 ; 分隔
+func (es Errors) Error() string {
+	messages := make([]string, 0, len(es))
+	for _, fe := range es {
+		messages = append(messages, fe.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// MarshalJSON 以结构化的方式输出所有字段错误
+func (es Errors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"message": "validation failed",
+		"errors":  []*FieldError(es),
+	})
+}
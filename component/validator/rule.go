@@ -0,0 +1,261 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 5:10 下午
+// version: 1.0.0
+// desc   : 校验规则
+
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Func 校验函数
+//
+// value 为字段值（已去除指针），param 为规则参数，parent 为字段所属的结构体，单个参数校验时无效
+type Func func(value reflect.Value, param string, parent reflect.Value) bool
+
+var (
+	mu         sync.RWMutex
+	validators = make(map[string]Func) // 自定义校验规则
+	patterns   sync.Map                // 已编译的正则
+
+	regEmail = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+	// 内置校验规则
+	builtins = map[string]Func{
+		"required": func(value reflect.Value, param string, parent reflect.Value) bool {
+			return value.IsValid() && !value.IsZero()
+		},
+		"min": func(value reflect.Value, param string, parent reflect.Value) bool {
+			size, ok := measure(value)
+			limit, err := strconv.ParseFloat(param, 64)
+			return ok && err == nil && size >= limit
+		},
+		"max": func(value reflect.Value, param string, parent reflect.Value) bool {
+			size, ok := measure(value)
+			limit, err := strconv.ParseFloat(param, 64)
+			return ok && err == nil && size <= limit
+		},
+		"len": func(value reflect.Value, param string, parent reflect.Value) bool {
+			size, ok := measure(value)
+			limit, err := strconv.ParseFloat(param, 64)
+			return ok && err == nil && size == limit
+		},
+		"regexp": func(value reflect.Value, param string, parent reflect.Value) bool {
+			reg, err := compile(param)
+			return err == nil && value.Kind() == reflect.String && reg.MatchString(value.String())
+		},
+		"enum": func(value reflect.Value, param string, parent reflect.Value) bool {
+			text := stringOf(value)
+			for _, item := range strings.Split(param, "|") {
+				if item == text {
+					return true
+				}
+			}
+			return false
+		},
+		"email": func(value reflect.Value, param string, parent reflect.Value) bool {
+			return value.Kind() == reflect.String && regEmail.MatchString(value.String())
+		},
+		"url": func(value reflect.Value, param string, parent reflect.Value) bool {
+			if value.Kind() != reflect.String {
+				return false
+			}
+			u, err := url.ParseRequestURI(value.String())
+			return err == nil && u.Scheme != "" && u.Host != ""
+		},
+		"eqfield":  crossField(func(result int) bool { return result == 0 }),
+		"nefield":  crossField(func(result int) bool { return result != 0 }),
+		"gtfield":  crossField(func(result int) bool { return result > 0 }),
+		"gtefield": crossField(func(result int) bool { return result >= 0 }),
+		"ltfield":  crossField(func(result int) bool { return result < 0 }),
+		"ltefield": crossField(func(result int) bool { return result <= 0 }),
+	}
+
+	// 内置规则的错误描述
+	messages = map[string]string{
+		"required": "is required",
+		"min":      "must be at least %v",
+		"max":      "must be at most %v",
+		"len":      "must have length %v",
+		"regexp":   "must match %v",
+		"enum":     "must be one of [%v]",
+		"email":    "must be a valid email address",
+		"url":      "must be a valid url",
+		"eqfield":  "must be equal to field [%v]",
+		"nefield":  "must not be equal to field [%v]",
+		"gtfield":  "must be greater than field [%v]",
+		"gtefield": "must be greater than or equal to field [%v]",
+		"ltfield":  "must be less than field [%v]",
+		"ltefield": "must be less than or equal to field [%v]",
+	}
+)
+
+// Register 注册自定义校验规则
+//
+// 同名规则会覆盖内置规则
+func Register(name string, fn Func) {
+	mu.Lock()
+	defer mu.Unlock()
+	validators[name] = fn
+}
+
+// lookup 查找校验规则
+func lookup(name string) Func {
+	mu.RLock()
+	defer mu.RUnlock()
+	if fn, ok := validators[name]; ok {
+		return fn
+	}
+	return builtins[name]
+}
+
+// checkParam 检查内置规则的参数，被自定义规则覆盖时不检查
+//
+// 注册时即可发现错误的参数，而不是每次校验都失败
+func checkParam(name, param string) error {
+	mu.RLock()
+	_, custom := validators[name]
+	mu.RUnlock()
+	if custom {
+		return nil
+	}
+	switch name {
+	case "min", "max", "len":
+		if _, err := strconv.ParseFloat(param, 64); err != nil {
+			return fmt.Errorf("the param of validation rule [%v] must be a number, but [%v]", name, param)
+		}
+	case "regexp":
+		if _, err := compile(param); err != nil {
+			return fmt.Errorf("the param of validation rule [%v] is not a valid regexp: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// crossField 与同一结构体中的其他字段比较
+func crossField(matched func(result int) bool) Func {
+	return func(value reflect.Value, param string, parent reflect.Value) bool {
+		if !parent.IsValid() || parent.Kind() != reflect.Struct {
+			return false
+		}
+		other := parent.FieldByName(param)
+		for other.Kind() == reflect.Ptr && !other.IsNil() {
+			other = other.Elem()
+		}
+		result, ok := compare(value, other)
+		return ok && matched(result)
+	}
+}
+
+// compare 比较两个值，支持数值、字符串和 time.Time
+func compare(a, b reflect.Value) (int, bool) {
+	if !a.IsValid() || !b.IsValid() {
+		return 0, false
+	}
+	if a.Type() == typeTime && b.Type() == typeTime {
+		ta, tb := a.Interface().(time.Time), b.Interface().(time.Time)
+		switch {
+		case ta.Before(tb):
+			return -1, true
+		case ta.After(tb):
+			return 1, true
+		}
+		return 0, true
+	}
+	if a.Kind() == reflect.String && b.Kind() == reflect.String {
+		return strings.Compare(a.String(), b.String()), true
+	}
+	na, okA := number(a)
+	nb, okB := number(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	switch {
+	case na < nb:
+		return -1, true
+	case na > nb:
+		return 1, true
+	}
+	return 0, true
+}
+
+// measure 数值取其值，字符串、切片、数组和 map 取其长度
+func measure(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true
+	}
+	return number(value)
+}
+
+// number 获取数值
+func number(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+	return 0, false
+}
+
+// stringOf 获取值的字符串形式
+func stringOf(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	}
+	if value.IsValid() && value.CanInterface() {
+		return fmt.Sprint(value.Interface())
+	}
+	return ""
+}
+
+// compile 编译并缓存正则
+func compile(expr string) (*regexp.Regexp, error) {
+	if reg, ok := patterns.Load(expr); ok {
+		return reg.(*regexp.Regexp), nil
+	}
+	reg, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(expr, reg)
+	return reg, nil
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 4:40 下午
+// version: 1.0.0
+// desc   : 参数校验器
+//			参数装配完成后，按 validate 标签或 Ship 上注册的规则校验参数
+
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/yhyzgn/gox/util"
+)
+
+const (
+	// TagValidate 校验规则标签，多个规则用 , 分隔，如 validate:"required,min=1,max=10"
+	// regexp 规则需放在最后，其参数可以包含 ,
+	TagValidate = "validate"
+)
+
+var (
+	typeTime = reflect.TypeOf(time.Time{})
+
+	// 用于确定字段名的标签
+	nameTags = []string{"json", "query", "form", "path", "header", "cookie", "param"}
+)
+
+// Rule 一条校验规则
+type Rule struct {
+	Name  string // 规则名
+	Param string // 规则参数
+}
+
+// Validate 按 validate 标签校验结构体，包括嵌套结构体
+//
+// 校验不通过时返回 Errors，规则有误时返回普通错误
+func Validate(bean interface{}) error {
+	value := reflect.ValueOf(bean)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	errs := make(Errors, 0)
+	if err := validateStruct("", value, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Var 按规则校验单个参数
+//
+// rules 中的每一项为一条规则，如 min=1、enum=a|b|c
+func Var(name string, bean interface{}, rules ...string) error {
+	parsed, err := ParseRules(rules...)
+	if err != nil {
+		return err
+	}
+
+	errs := make(Errors, 0)
+	if err := validateValue(name, reflect.ValueOf(bean), reflect.Value{}, parsed, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ParseRules 解析校验规则，并检查规则是否已注册，以及内置规则的参数是否合法
+func ParseRules(rules ...string) ([]Rule, error) {
+	parsed := make([]Rule, 0, len(rules))
+	for _, rule := range rules {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		name, param := rule, ""
+		if i := strings.IndexByte(rule, '='); i > -1 {
+			name, param = rule[:i], rule[i+1:]
+		}
+		if lookup(name) == nil {
+			return nil, fmt.Errorf("unknown validation rule [%v]", name)
+		}
+		if err := checkParam(name, param); err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, Rule{Name: name, Param: param})
+	}
+	return parsed, nil
+}
+
+// parseTag 解析 validate 标签
+func parseTag(tag string) ([]Rule, error) {
+	rules := make([]string, 0)
+	for tag != "" {
+		// regexp 参数中可能有 , ，直接取剩余部分
+		if strings.HasPrefix(tag, "regexp=") {
+			rules = append(rules, tag)
+			break
+		}
+		rule := tag
+		if i := strings.IndexByte(tag, ','); i > -1 {
+			rule, tag = tag[:i], tag[i+1:]
+		} else {
+			tag = ""
+		}
+		rules = append(rules, rule)
+	}
+	return ParseRules(rules...)
+}
+
+// validateStruct 逐个校验结构体字段
+func validateStruct(prefix string, value reflect.Value, errs *Errors) error {
+	tp := value.Type()
+	for i := 0; i < tp.NumField(); i++ {
+		field := tp.Field(i)
+		fieldValue := value.Field(i)
+		name := prefix + fieldName(field)
+
+		if tag, ok := field.Tag.Lookup(TagValidate); ok {
+			rules, err := parseTag(tag)
+			if err != nil {
+				return fmt.Errorf("the field [%v.%v] has invalid rules: %v", tp.Name(), field.Name, err)
+			}
+			if err = validateValue(name, fieldValue, value, rules, errs); err != nil {
+				return err
+			}
+		}
+
+		// 嵌套结构体
+		nested := fieldValue
+		for nested.Kind() == reflect.Ptr && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct && nested.Type() != typeTime {
+			// 匿名嵌套的字段与外层字段同级
+			nestedPrefix := name + "."
+			if field.Anonymous {
+				nestedPrefix = prefix
+			}
+			if err := validateStruct(nestedPrefix, nested, errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateValue 按规则校验字段值
+//
+// 指针为 nil 时只校验 required，其他规则跳过
+func validateValue(name string, value, parent reflect.Value, rules []Rule, errs *Errors) error {
+	for _, rule := range rules {
+		current := value
+		for current.Kind() == reflect.Ptr && !current.IsNil() {
+			current = current.Elem()
+		}
+		if rule.Name != "required" && (!current.IsValid() || current.Kind() == reflect.Ptr) {
+			continue
+		}
+
+		fn := lookup(rule.Name)
+		if fn == nil {
+			return fmt.Errorf("unknown validation rule [%v]", rule.Name)
+		}
+		if !fn(current, rule.Param, parent) {
+			*errs = append(*errs, newFieldError(name, rule, current))
+		}
+	}
+	return nil
+}
+
+// fieldName 获取字段在请求中的名称
+func fieldName(field reflect.StructField) string {
+	for _, tag := range nameTags {
+		if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+	return util.FirstToLower(field.Name)
+}
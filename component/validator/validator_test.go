@@ -0,0 +1,145 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 5:50 下午
+// version: 1.0.0
+// desc   : 参数校验器测试
+
+package validator
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end" validate:"gtfield=Start"`
+}
+
+type User struct {
+	Name     string   `json:"name" validate:"required,min=2,max=8"`
+	Age      int      `query:"age" validate:"min=18"`
+	Gender   string   `form:"gender" validate:"enum=male|female"`
+	Email    string   `validate:"email"`
+	Site     string   `validate:"url"`
+	Code     string   `validate:"regexp=^[a-z]{2,3}$"`
+	Tags     []string `validate:"max=2"`
+	Password string   `validate:"len=6"`
+	Confirm  string   `validate:"eqfield=Password"`
+	Nickname *string  `validate:"min=2"`
+	Address  *Address `json:"address"`
+	Period
+}
+
+func TestValidate(t *testing.T) {
+	valid := &User{
+		Name:     "jason",
+		Age:      20,
+		Gender:   "male",
+		Email:    "yhyzgn@gmail.com",
+		Site:     "https://github.com/yhyzgn/gox",
+		Code:     "gox",
+		Tags:     []string{"a"},
+		Password: "123456",
+		Confirm:  "123456",
+		Address:  &Address{City: "Chengdu"},
+		Period:   Period{Start: time.Unix(0, 0), End: time.Unix(1, 0)},
+	}
+	if err := Validate(valid); err != nil {
+		t.Fatalf("should be valid, but %v", err)
+	}
+
+	invalid := &User{
+		Name:     "j",
+		Age:      12,
+		Gender:   "unknown",
+		Email:    "yhyzgn",
+		Site:     "github.com",
+		Code:     "GOX",
+		Tags:     []string{"a", "b", "c"},
+		Password: "123",
+		Confirm:  "456",
+		Address:  &Address{},
+		Period:   Period{Start: time.Unix(1, 0), End: time.Unix(0, 0)},
+	}
+	err := Validate(invalid)
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("should be validation errors, but %v", err)
+	}
+
+	fields := make([]string, 0, len(errs))
+	for _, fe := range errs {
+		fields = append(fields, fe.Field+":"+fe.Rule)
+	}
+	expected := []string{"name:min", "age:min", "gender:enum", "email:email", "site:url", "code:regexp", "tags:max", "password:len", "confirm:eqfield", "address.city:required", "end:gtfield"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("should be reported as\n%v\nbut\n%v", expected, fields)
+	}
+
+	bs, _ := json.Marshal(errs)
+	if !strings.Contains(string(bs), `"field":"address.city"`) {
+		t.Errorf("errors should be marshaled with fields, but %s", bs)
+	}
+}
+
+func TestVar(t *testing.T) {
+	Register("even", func(value reflect.Value, param string, parent reflect.Value) bool {
+		return value.Int()%2 == 0
+	})
+
+	if err := Var("size", 4, "min=2", "even"); err != nil {
+		t.Errorf("should be valid, but %v", err)
+	}
+	if err := Var("size", 3, "min=2", "even"); err == nil || err.(Errors)[0].Rule != "even" {
+		t.Errorf("custom rule should be reported, but %v", err)
+	}
+	if err := Var("size", 3, "unknown"); err == nil {
+		t.Error("unknown rule should be reported")
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	if _, err := ParseRules("required", "min=1", "max=2.5", "regexp=^[a-z]+$"); err != nil {
+		t.Errorf("should be valid, but %v", err)
+	}
+	for _, rule := range []string{"min=", "max=ten", "len=1,2", "regexp=^[a-z+$"} {
+		if _, err := ParseRules(rule); err == nil {
+			t.Errorf("invalid param of rule [%v] should be reported", rule)
+		}
+	}
+
+	// 被自定义规则覆盖时不检查参数
+	Register("len", func(value reflect.Value, param string, parent reflect.Value) bool {
+		return true
+	})
+	defer func() {
+		mu.Lock()
+		delete(validators, "len")
+		mu.Unlock()
+	}()
+	if _, err := ParseRules("len=short"); err != nil {
+		t.Errorf("custom rule should not be checked, but %v", err)
+	}
+}
@@ -22,28 +22,95 @@ package core
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/yhyzgn/gog"
 
 	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/component/interceptor"
+	"github.com/yhyzgn/gox/wire"
 )
 
 // Mapper 处理器映射器
 type Mapper struct {
-	contextPath string
-	path        string
-	ctrl        Controller
+	wires        *wire.Wires // 处理器映射集合
+	contextPath  string
+	path         string
+	ctrl         Controller
+	parent       *Mapper                   // 所属的上级分组
+	interceptors []interceptor.Interceptor // 当前分组的拦截器
+	advices      []*common.ErrorAdvice     // 当前分组的错误处理器
 }
 
 // NewMapper 创建映射器
+//
+// 处理器注册到全局的映射集合中，仅适用于默认服务 gox.Default()，其他服务使用 NewWiresMapper
 func NewMapper(contextPath, path string, ctrl Controller) *Mapper {
+	return NewWiresMapper(wire.Instance, contextPath, path, ctrl)
+}
+
+// NewWiresMapper 创建映射器，处理器注册到指定的映射集合中
+func NewWiresMapper(wires *wire.Wires, contextPath, path string, ctrl Controller) *Mapper {
 	return &Mapper{
+		wires:       wires,
 		contextPath: contextPath,
 		path:        path,
 		ctrl:        ctrl,
 	}
 }
 
+// Group 创建路由分组
+//
+// 分组内的处理器 path 都以 prefix 开头，并继承当前映射器的所有拦截器
+func (mp *Mapper) Group(prefix string, group func(*Mapper)) *Mapper {
+	if group == nil {
+		return mp
+	}
+	group(&Mapper{
+		wires:       mp.wires,
+		contextPath: mp.contextPath,
+		path:        strings.TrimSuffix(mp.path, "/") + "/" + strings.TrimPrefix(prefix, "/"),
+		ctrl:        mp.ctrl,
+		parent:      mp,
+	})
+	return mp
+}
+
+// Use 添加当前分组的拦截器
+//
+// 只作用于之后注册的处理器，执行顺序在全局拦截器之后
+func (mp *Mapper) Use(interceptors ...interceptor.Interceptor) *Mapper {
+	mp.interceptors = append(mp.interceptors, interceptors...)
+	return mp
+}
+
+// Interceptors 获取当前分组生效的所有拦截器，上级分组的在前
+func (mp *Mapper) Interceptors() []interceptor.Interceptor {
+	interceptors := make([]interceptor.Interceptor, 0)
+	if mp.parent != nil {
+		interceptors = append(interceptors, mp.parent.Interceptors()...)
+	}
+	return append(interceptors, mp.interceptors...)
+}
+
+// HandleError 添加当前分组的错误处理器
+//
+// target 与 errors.As 的第二个参数相同，如 new(*common.HTTPError)
+// 只作用于之后注册的处理器，优先于上级分组和全局的错误处理器
+func (mp *Mapper) HandleError(target interface{}, handler common.ErrorHandlerFunc) *Mapper {
+	mp.advices = append(mp.advices, common.NewErrorAdvice(target, handler))
+	return mp
+}
+
+// ErrorAdvices 获取当前分组生效的所有错误处理器，当前分组的在前
+func (mp *Mapper) ErrorAdvices() []*common.ErrorAdvice {
+	advices := append(make([]*common.ErrorAdvice, 0), mp.advices...)
+	if mp.parent != nil {
+		advices = append(advices, mp.parent.ErrorAdvices()...)
+	}
+	return advices
+}
+
 // Request 注册一个新的处理器
 func (mp *Mapper) Request(paths ...string) *Ship {
 	if paths == nil || len(paths) == 0 {
@@ -21,18 +21,28 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/yhyzgn/gox/util"
 
 	"github.com/yhyzgn/gog"
+	"github.com/yhyzgn/gox/codec"
 	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/component/validator"
 	"github.com/yhyzgn/gox/wire"
 )
 
+var (
+	typeContext  = reflect.TypeOf((*context.Context)(nil)).Elem()
+	typePageable = reflect.TypeOf(common.Pageable{})
+)
+
 // Ship 路由关系映射器
 type Ship struct {
 	contextPath string             // 根路径
@@ -41,6 +51,10 @@ type Ship struct {
 	handlerFunc common.HandlerFunc // 配置的 处理器
 	methods     []common.Method    // http 请求方法列表
 	params      []*common.Param    // 配置的参数列表
+	produces    []string           // 可响应的媒体类型
+	consumes    []string           // 可接收的请求体媒体类型
+	maxBodySize int64              // 请求体的最大字节数
+	timeout     time.Duration      // 处理超时时间
 }
 
 // Mapping 完成一条 处理器关系 映射
@@ -58,20 +72,18 @@ func (sp *Ship) Mapping() *Mapper {
 	}
 
 	// 检查参数有效性
-	// 每个方法最多 2 个参数可以是 http.ResponseWriter 和 *http.Request
+	// http.ResponseWriter、*http.Request、context.Context 和 common.Pageable 参数自动注入
 	// 其他均是自定义参数，需要注册
 	x := v.Type()
 	paramCount := x.NumIn()
 
-	// 判断是否有 http.ResponseWriter 和 *http.Request 参数
+	// 自动注入的参数个数
 	// 便于参数有效性的判断
-	hasWriter, hasRequest := sp.hasResponseWriterAndRequest(x)
 	delta := 0
-	if hasWriter {
-		delta++
-	}
-	if hasRequest {
-		delta++
+	for i := 0; i < paramCount; i++ {
+		if isImplicitParam(x.In(i)) {
+			delta++
+		}
 	}
 
 	if paramCount > len(sp.params)+delta {
@@ -95,58 +107,87 @@ func (sp *Ship) Mapping() *Mapper {
 		if elemType.Kind() == reflect.Ptr {
 			elemType = elemType.Elem()
 		}
-		elemKind := elemType.Kind()
-		elemName := elemType.Name()
 
 		var param *common.Param
-		// http.ResponseWriter 或者 *http.Request
-		if elemType.PkgPath() == "net/http" && (elemKind == reflect.Interface && elemName == "ResponseWriter" || realType.Kind() == reflect.Ptr && elemKind == reflect.Struct && elemName == "Request") {
-			// http.ResponseWriter || *http.Request
+		if isImplicitParam(realType) {
+			// http.ResponseWriter || *http.Request || context.Context || common.Pageable
 			param = new(common.Param)
 		} else {
 			// 已注册过的参数 映射 Type
 			param = sp.params[pos]
+			pos++
 		}
 		param.RealType = realType
 		param.IsPtr = realType != elemType
 		param.ElemType = elemType
 		tempParams[i] = param
-		pos++
 	}
 	sp.params = tempParams
 
-	// 注册 每一条映射关系
-	for _, path := range sp.resolvePath() {
-		wire.Instance.Mapping(path, common.Handler(v), sp.methods, sp.params)
+	// 校验规则有误时直接终止
+	for _, param := range sp.params {
+		if _, err := validator.ParseRules(param.Validations...); err != nil {
+			gog.FatalF("The param [%v] has invalid validation rules: %v", param.Name, err)
+		}
 	}
-	return sp.mapper
-}
 
-func (sp *Ship) hasResponseWriterAndRequest(tp reflect.Type) (hasWriter bool, hasRequest bool) {
-	paramCount := tp.NumIn()
-	for i := 0; i < paramCount; i++ {
-		realType := tp.In(i)
-		elemType := realType
-		if elemType.Kind() == reflect.Ptr {
-			elemType = elemType.Elem()
-		}
+	// 注册 每一条映射关系
+	// 分组拦截器在注册时确定，无需每次请求再匹配
+	interceptors := sp.mapper.Interceptors()
 
-		if elemType.PkgPath() == "net/http" {
-			if elemType.Kind() == reflect.Interface && elemType.Name() == "ResponseWriter" {
-				hasWriter = true
-				continue
-			}
+	// 响应的媒体类型需要有对应的编码器
+	for _, mediaType := range sp.produces {
+		if codec.GetEncoder(mediaType) == nil {
+			gog.FatalF("There is no encoder registered for media type [%v].", mediaType)
+		}
+	}
 
-			if realType.Kind() == reflect.Ptr && elemType.Kind() == reflect.Struct && elemType.Name() == "Request" {
-				hasRequest = true
-			}
+	// 请求体的媒体类型需要有对应的解码器
+	for _, mediaType := range sp.consumes {
+		if codec.GetDecoder(mediaType) == nil {
+			gog.FatalF("There is no decoder registered for media type [%v].", mediaType)
 		}
+	}
 
-		if hasWriter && hasRequest {
-			return
+	// 路由非法或冲突时直接终止
+	for _, path := range sp.resolvePath() {
+		hw := &wire.HandlerWire{
+			Path:         path,
+			Handler:      common.Handler(v),
+			Methods:      sp.methods,
+			Params:       sp.params,
+			Interceptors: interceptors,
+			Produces:     sp.produces,
+			Consumes:     sp.consumes,
+			MaxBodySize:  sp.maxBodySize,
+			Timeout:      sp.timeout,
+			ErrorAdvices: sp.mapper.ErrorAdvices(),
+		}
+		if err := sp.mapper.wires.Add(hw); err != nil {
+			gog.Fatal(err)
 		}
 	}
-	return
+	return sp.mapper
+}
+
+// isImplicitParam 是否是自动注入的参数
+//
+// http.ResponseWriter、*http.Request、context.Context 和 common.Pageable
+func isImplicitParam(realType reflect.Type) bool {
+	if realType == typeContext {
+		return true
+	}
+	elemType := realType
+	if elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType == typePageable {
+		return true
+	}
+	if elemType.PkgPath() != "net/http" {
+		return false
+	}
+	return elemType.Kind() == reflect.Interface && elemType.Name() == "ResponseWriter" || realType.Kind() == reflect.Ptr && elemType.Kind() == reflect.Struct && elemType.Name() == "Request"
 }
 
 // resolvePath 用 / 处理 path，构建标准 url path
@@ -201,6 +242,24 @@ func (sp *Ship) Header(name string) *Ship {
 	return sp
 }
 
+// Cookie 注册 cookie 参数，必需参数
+//
+// 接收 cookie 值或者 *http.Cookie
+func (sp *Ship) Cookie(name string) *Ship {
+	param := common.NewParam(name, true, false, false, false)
+	param.InCookie = true
+	sp.params = append(sp.params, param)
+	return sp
+}
+
+// OptionalCookie 注册 cookie 参数，可空
+func (sp *Ship) OptionalCookie(name string) *Ship {
+	param := common.NewParam(name, false, false, false, false)
+	param.InCookie = true
+	sp.params = append(sp.params, param)
+	return sp
+}
+
 // Required 注册普通参数，必需参数
 func (sp *Ship) Required(name string) *Ship {
 	sp.params = append(sp.params, common.NewParam(name, true, false, false, false))
@@ -214,6 +273,9 @@ func (sp *Ship) Param(name string) *Ship {
 }
 
 // PathVariable 注册RESTful格式参数
+//
+// 对应 path 中的 {name}、{name:int}、{name:[0-9]+}、{name?} 或 {name...} 段
+// 可选段未匹配时为零值，通配段的值包含剩余路径中的 /
 func (sp *Ship) PathVariable(name string) *Ship {
 	sp.params = append(sp.params, common.NewParam(name, true, false, true, false))
 	return sp
@@ -224,3 +286,104 @@ func (sp *Ship) Body(name string) *Ship {
 	sp.params = append(sp.params, common.NewParam(name, true, false, false, true))
 	return sp
 }
+
+// Bean 注册 IOC 容器中的参数
+//
+// 按名称查找单例，名称为空时按参数类型查找
+func (sp *Ship) Bean(name string) *Ship {
+	param := common.NewParam(name, true, false, false, false)
+	param.InBean = true
+	sp.params = append(sp.params, param)
+	return sp
+}
+
+// Attribute 注册 request 属性参数，如拦截器中设置的登录用户
+//
+// 在拦截器 PreHandle() 之后取值，属性不存在时为零值
+func (sp *Ship) Attribute(key string) *Ship {
+	param := common.NewParam(key, false, false, false, false)
+	param.InAttribute = true
+	sp.params = append(sp.params, param)
+	return sp
+}
+
+// Produces 配置可响应的媒体类型，如 application/json、application/xml
+//
+// 按 Accept 请求头在其中协商，都不满足时响应 406
+// 媒体类型需要在注册路由前注册编码器，见 codec.Register
+func (sp *Ship) Produces(mediaTypes ...string) *Ship {
+	sp.produces = append(sp.produces, mediaTypes...)
+	return sp
+}
+
+// Consumes 配置可接收的请求体媒体类型，如 application/json、application/x-www-form-urlencoded
+//
+// 请求体的 Content-Type 不在其中时响应 415
+// 媒体类型需要在注册路由前注册解码器，见 codec.RegisterDecoder
+func (sp *Ship) Consumes(mediaTypes ...string) *Ship {
+	sp.consumes = append(sp.consumes, mediaTypes...)
+	return sp
+}
+
+// MaxBodySize 配置请求体的最大字节数，超过时响应 413
+//
+// 优先于全局配置
+func (sp *Ship) MaxBodySize(size int64) *Ship {
+	sp.maxBodySize = size
+	return sp
+}
+
+// Timeout 配置处理超时时间
+//
+// 超时后取消请求的 context.Context，并响应 503（可通过 GoX.TimeoutStatus 改为 504），处理器之后的响应将被丢弃
+func (sp *Ship) Timeout(timeout time.Duration) *Ship {
+	sp.timeout = timeout
+	return sp
+}
+
+// Validate 为最后注册的参数配置校验规则
+//
+// 每一项为一条规则，如 Validate("min=1", "max=10")，支持 validator.Register 注册的自定义规则
+func (sp *Ship) Validate(rules ...string) *Ship {
+	if len(sp.params) == 0 {
+		gog.FatalF("The validation rules %v must be configured after a param.", rules)
+	}
+	param := sp.params[len(sp.params)-1]
+	param.Validations = append(param.Validations, rules...)
+	return sp
+}
+
+// Min 最小值，字符串、切片和 map 为最小长度
+func (sp *Ship) Min(min float64) *Ship {
+	return sp.Validate("min=" + strconv.FormatFloat(min, 'f', -1, 64))
+}
+
+// Max 最大值，字符串、切片和 map 为最大长度
+func (sp *Ship) Max(max float64) *Ship {
+	return sp.Validate("max=" + strconv.FormatFloat(max, 'f', -1, 64))
+}
+
+// Len 长度，数值类型时为具体值
+func (sp *Ship) Len(length int) *Ship {
+	return sp.Validate("len=" + strconv.Itoa(length))
+}
+
+// Regexp 正则匹配
+func (sp *Ship) Regexp(expr string) *Ship {
+	return sp.Validate("regexp=" + expr)
+}
+
+// Enum 枚举值
+func (sp *Ship) Enum(values ...string) *Ship {
+	return sp.Validate("enum=" + strings.Join(values, "|"))
+}
+
+// Email 邮箱地址
+func (sp *Ship) Email() *Ship {
+	return sp.Validate("email")
+}
+
+// URL 网址
+func (sp *Ship) URL() *Ship {
+	return sp.Validate("url")
+}
@@ -25,7 +25,9 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/yhyzgn/gox/common"
 	"github.com/yhyzgn/gox/resolver"
+	"github.com/yhyzgn/gox/util"
 
 	"github.com/yhyzgn/gox/resource"
 )
@@ -35,12 +37,21 @@ type GoXContext struct {
 	contextPath       string                    // 根路径
 	reader            *resource.Reader          // 资源读取器
 	errorHandlers     sync.Map                  // 错误处理器，每个错误码对应一个处理器
+	hasErrorHandlers  bool                      // 是否添加过错误码处理器
+	errorAdvices      []*common.ErrorAdvice     // 全局的错误处理器，按错误类型匹配
 	staticDir         string                    // 静态资源文件夹路径
 	notFound          http.HandlerFunc          // 404错误处理器
 	unSupportedMethod http.HandlerFunc          // 方法不支持错误处理器
 	argumentResolver  resolver.ArgumentResolver // 参数处理器
+	paramResolvers    []resolver.ParamResolver  // 单个参数的处理器，按注册顺序匹配
 	resultResolver    resolver.ResultResolver   // 结果处理器
 	errorResolver     resolver.ErrorResolver    // 全局异常处理器
+	maxBodySize       int64                     // 请求体的最大字节数，不大于 0 时不限制
+	timeoutStatus     int                       // 处理超时时响应的状态码
+	pageSize          int                       // 分页请求的默认每页条数
+	maxPageSize       int                       // 分页请求的最大每页条数，不大于 0 时不限制
+	rePanic           bool                      // panic 处理后是否继续抛出，便于开发时调试
+	cookieKey         []byte                    // cookie 签名和加密的密钥
 }
 
 var (
@@ -50,24 +61,40 @@ var (
 
 func init() {
 	once.Do(func() {
-		current = &GoXContext{
-			reader:   resource.NewReader(),
-			notFound: http.NotFound,
-			unSupportedMethod: func(writer http.ResponseWriter, request *http.Request) {
-				http.Error(writer, fmt.Sprintf("Unsupported http method [%v].", request.Method), http.StatusMethodNotAllowed)
-			},
-			argumentResolver: resolver.NewSimpleArgumentResolver(),
-			resultResolver:   resolver.NewSimpleResultResolver(),
-			errorResolver:    resolver.NewSimpleErrorResolver(),
-		}
+		current = NewGoXContext()
 	})
 }
 
-// C 获取当前上下文对象
+// NewGoXContext 创建新的上下文对象
+func NewGoXContext() *GoXContext {
+	return &GoXContext{
+		reader:   resource.NewReader(),
+		notFound: http.NotFound,
+		unSupportedMethod: func(writer http.ResponseWriter, request *http.Request) {
+			http.Error(writer, fmt.Sprintf("Unsupported http method [%v].", request.Method), http.StatusMethodNotAllowed)
+		},
+		argumentResolver: resolver.NewSimpleArgumentResolver(),
+		resultResolver:   resolver.NewSimpleResultResolver(),
+		errorResolver:    resolver.NewSimpleErrorResolver(),
+		pageSize:         common.DefaultPageSize,
+		maxPageSize:      common.MaxPageSize,
+		timeoutStatus:    http.StatusServiceUnavailable,
+	}
+}
+
+// C 获取默认的上下文对象
 func C() *GoXContext {
 	return current
 }
 
+// FromRequest 获取请求所属的上下文对象，不存在时为默认的上下文对象
+func FromRequest(request *http.Request) *GoXContext {
+	if c, ok := util.GetRequestAttribute(request, common.RequestContextName).(*GoXContext); ok {
+		return c
+	}
+	return current
+}
+
 // Read 读取资源文件
 func (c *GoXContext) Read(filename string) (data []byte, errs error) {
 	return c.reader.Read(filename)
@@ -108,17 +135,47 @@ func (c *GoXContext) SetUnSupportMethodHandler(handler http.HandlerFunc) *GoXCon
 }
 
 // AddErrorHandler 添加错误码处理器
+//
+// 响应该状态码时，改由处理器响应
 func (c *GoXContext) AddErrorHandler(statusCode int, handler http.HandlerFunc) *GoXContext {
 	c.errorHandlers.Store(statusCode, handler)
+	c.hasErrorHandlers = true
+	return c
+}
+
+// AddErrorTypeHandler 添加全局的错误处理器
+//
+// target 与 errors.As 的第二个参数相同，如 new(*common.HTTPError)
+// 控制器或路由分组的错误处理器优先，都不匹配时交给异常处理器
+func (c *GoXContext) AddErrorTypeHandler(target interface{}, handler common.ErrorHandlerFunc) *GoXContext {
+	c.errorAdvices = append(c.errorAdvices, common.NewErrorAdvice(target, handler))
 	return c
 }
 
+// GetErrorAdvices 获取全局的错误处理器
+func (c *GoXContext) GetErrorAdvices() []*common.ErrorAdvice {
+	return c.errorAdvices
+}
+
+// HasErrorHandlers 是否添加过错误码处理器
+func (c *GoXContext) HasErrorHandlers() bool {
+	return c.hasErrorHandlers
+}
+
 // SetArgumentResolver 设置参数处理器
 func (c *GoXContext) SetArgumentResolver(resolver resolver.ArgumentResolver) *GoXContext {
 	c.argumentResolver = resolver
 	return c
 }
 
+// AddParamResolver 添加单个参数的处理器
+//
+// 按添加顺序匹配，优先于内置的参数来源
+func (c *GoXContext) AddParamResolver(resolvers ...resolver.ParamResolver) *GoXContext {
+	c.paramResolvers = append(c.paramResolvers, resolvers...)
+	return c
+}
+
 // SetResultResolver 设置结果处理器
 func (c *GoXContext) SetResultResolver(resolver resolver.ResultResolver) *GoXContext {
 	c.resultResolver = resolver
@@ -131,11 +188,47 @@ func (c *GoXContext) SetErrorResolver(resolver resolver.ErrorResolver) *GoXConte
 	return c
 }
 
+// SetMaxBodySize 设置请求体的最大字节数，不大于 0 时不限制
+func (c *GoXContext) SetMaxBodySize(size int64) *GoXContext {
+	c.maxBodySize = size
+	return c
+}
+
+// SetTimeoutStatus 设置处理超时时响应的状态码，默认为 503
+func (c *GoXContext) SetTimeoutStatus(status int) *GoXContext {
+	c.timeoutStatus = status
+	return c
+}
+
+// SetPageSize 设置分页请求的默认每页条数和最大每页条数
+func (c *GoXContext) SetPageSize(size, maxSize int) *GoXContext {
+	c.pageSize = size
+	c.maxPageSize = maxSize
+	return c
+}
+
+// SetCookieKey 设置 cookie 签名和加密的密钥
+func (c *GoXContext) SetCookieKey(key []byte) *GoXContext {
+	c.cookieKey = key
+	return c
+}
+
+// SetRePanic 设置 panic 处理后是否继续抛出
+func (c *GoXContext) SetRePanic(rePanic bool) *GoXContext {
+	c.rePanic = rePanic
+	return c
+}
+
 // GetArgumentResolver 获取参数处理器
 func (c *GoXContext) GetArgumentResolver() resolver.ArgumentResolver {
 	return c.argumentResolver
 }
 
+// GetParamResolvers 获取所有单个参数的处理器
+func (c *GoXContext) GetParamResolvers() []resolver.ParamResolver {
+	return c.paramResolvers
+}
+
 // GetResultResolver 获取结果处理器
 func (c *GoXContext) GetResultResolver() resolver.ResultResolver {
 	return c.resultResolver
@@ -161,6 +254,39 @@ func (c *GoXContext) GetUnSupportMethodHandler() http.HandlerFunc {
 	return c.unSupportedMethod
 }
 
+// GetMaxBodySize 获取请求体的最大字节数
+func (c *GoXContext) GetMaxBodySize() int64 {
+	return c.maxBodySize
+}
+
+// GetTimeoutStatus 获取处理超时时响应的状态码，未配置时为 503
+func (c *GoXContext) GetTimeoutStatus() int {
+	if c.timeoutStatus <= 0 {
+		return http.StatusServiceUnavailable
+	}
+	return c.timeoutStatus
+}
+
+// GetCookieKey 获取 cookie 签名和加密的密钥
+func (c *GoXContext) GetCookieKey() []byte {
+	return c.cookieKey
+}
+
+// GetPageSize 获取分页请求的默认每页条数
+func (c *GoXContext) GetPageSize() int {
+	return c.pageSize
+}
+
+// GetMaxPageSize 获取分页请求的最大每页条数
+func (c *GoXContext) GetMaxPageSize() int {
+	return c.maxPageSize
+}
+
+// IsRePanic panic 处理后是否继续抛出
+func (c *GoXContext) IsRePanic() bool {
+	return c.rePanic
+}
+
 // GetErrorHandler 获取错误码处理器
 func (c *GoXContext) GetErrorHandler(statusCode int) http.HandlerFunc {
 	handler, ok := c.errorHandlers.Load(statusCode)
@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"sync"
 	"syscall"
 
@@ -39,13 +40,23 @@ import (
 	"github.com/yhyzgn/gox/core"
 	"github.com/yhyzgn/gox/ctx"
 	"github.com/yhyzgn/gox/ioc"
+	"github.com/yhyzgn/gox/resource"
 	"github.com/yhyzgn/gox/util"
+	"github.com/yhyzgn/gox/wire"
 )
 
 // GoX MVC 服务处理器
+//
+// NewGoX() 创建的服务拥有各自的组件，Default() 和零值的 GoX{} 使用全局默认组件
 type GoX struct {
-	mu sync.RWMutex
-	ctx.GoXContext
+	mu   sync.RWMutex
+	once sync.Once // 零值时延迟使用全局默认组件
+	*ctx.GoXContext
+	wires               *wire.Wires                   // 处理器映射
+	provider            *ioc.Provider                 // IOC 容器
+	filterChain         *filter.Chain                 // 过滤器链
+	requestDispatcher   *dispatcher.RequestDispatcher // 请求分发器
+	interceptorRegister *interceptor.Register         // 拦截器注册器
 }
 
 var (
@@ -64,6 +75,7 @@ func init() {
 
 // ServeHTTP 接收处理请求
 func (gx *GoX) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	gx.lazyInit()
 	if request.RequestURI == "*" {
 		if request.ProtoAtLeast(1, 1) {
 			util.SetResponseWriterHeader(writer, "Connection", "closed")
@@ -72,8 +84,18 @@ func (gx *GoX) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 		return
 	}
 
-	// 每个请求 过滤器 开始标记
-	request = util.SetRequestAttribute(request, common.RequestFilterIndexName, 0)
+	// 配置了错误码处理器时，记录响应状态码，请求处理完成后交给对应的处理器
+	if gx.HasErrorHandlers() {
+		sw := newStatusWriter(writer, gx.GetErrorHandler)
+		writer = sw
+		defer sw.finish(request)
+	}
+
+	// 过滤器、分发器等任何地方的 panic 都转为 500
+	defer gx.recoverPanic(writer, request)
+
+	// 记录请求所属的上下文
+	request = util.SetRequestAttribute(request, common.RequestContextName, gx.GoXContext)
 
 	// -----------------------------------------------------------------------
 	// 过滤器
@@ -86,80 +108,238 @@ func (gx *GoX) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
 	// -----------------------------------------------------------------------
 
 	// 开始啦~
-	filterChain.DoFilter(writer, request)
+	gx.filterChain.DoFilter(writer, request)
+}
+
+// recoverPanic 恢复 panic，并交给异常处理器响应 500
+//
+// 分发器中已处理过的 panic 不再重复处理
+func (gx *GoX) recoverPanic(writer http.ResponseWriter, request *http.Request) {
+	value := recover()
+	if value == nil {
+		return
+	}
+	// 客户端已断开，交由 net/http 处理
+	if value == http.ErrAbortHandler {
+		panic(value)
+	}
+
+	pe, ok := value.(*common.PanicError)
+	if !ok {
+		pe = common.NewPanicError(value, debug.Stack(), "")
+	}
+	if !pe.Handled {
+		pe.Handled = true
+		gog.ErrorF("Panic occurred while serving request [%v %v]: %v\n%s", request.Method, request.URL.Path, pe.Value, pe.Stack)
+		gx.GetErrorResolver().Resolve(http.StatusInternalServerError, pe, writer)
+	}
+	if gx.IsRePanic() {
+		panic(pe)
+	}
 }
 
 // NewGoX 创建新服务
+//
+// 拥有各自的上下文、路由、过滤器链、拦截器注册器和 IOC 容器，与其他服务互不影响，可在同一进程中运行多个
+// 控制器依赖的对象需通过 gx.Provider() 注册，通过 ioc.C() 注册的只对 Default() 生效
 func NewGoX() *GoX {
-	return new(GoX)
+	gx := &GoX{
+		GoXContext:          ctx.NewGoXContext(),
+		wires:               wire.NewWires(),
+		provider:            ioc.NewProvider(),
+		filterChain:         filter.NewChain(),
+		requestDispatcher:   dispatcher.NewRequestDispatcher(),
+		interceptorRegister: interceptor.NewRegister(),
+	}
+	// 与默认服务一样，按类型注入资源读取器
+	gx.provider.Add(func() (instance interface{}) {
+		instance = resource.NewReader()
+		return
+	})
+	gx.requestDispatcher.SetContext(gx.GoXContext)
+	gx.requestDispatcher.SetWires(gx.wires)
+	gx.requestDispatcher.SetProvider(gx.provider)
+	gx.requestDispatcher.SetInterceptorRegister(gx.interceptorRegister)
+	gx.filterChain.SetContext(gx.GoXContext)
+	gx.filterChain.SetDispatcher(gx.requestDispatcher)
+	return gx
+}
+
+// Default 默认服务，兼容旧版的全局组件
+//
+// 使用 ctx.C()、wire.Instance、ioc.C() 以及包级的过滤器链、分发器和拦截器注册器，零值的 GoX{} 与其相同
+func Default() *GoX {
+	gx := new(GoX)
+	gx.lazyInit()
+	return gx
+}
+
+// lazyInit 零值的 GoX{} 使用全局默认组件
+func (gx *GoX) lazyInit() {
+	gx.once.Do(func() {
+		if gx.GoXContext != nil {
+			return
+		}
+		gx.GoXContext = ctx.C()
+		gx.wires = wire.Instance
+		gx.provider = ioc.C()
+		gx.filterChain = filterChain
+		gx.requestDispatcher = requestDispatcher
+		gx.interceptorRegister = interceptorRegister
+	})
 }
 
 // Read 读取资源文件
 func (gx *GoX) Read(filename string) (data []byte, errs error) {
-	return ctx.C().Read(filename)
+	gx.lazyInit()
+	return gx.GoXContext.Read(filename)
 }
 
 // Load 加载资源文件到实例
 func (gx *GoX) Load(filename string, bean interface{}) (err error) {
-	return ctx.C().Load(filename, bean)
+	gx.lazyInit()
+	return gx.GoXContext.Load(filename, bean)
+}
+
+// Provider 获取 IOC 容器
+func (gx *GoX) Provider() *ioc.Provider {
+	gx.lazyInit()
+	return gx.provider
+}
+
+// Filters 按执行顺序获取所有过滤器
+func (gx *GoX) Filters() []filter.Info {
+	gx.lazyInit()
+	return gx.filterChain.Filters()
 }
 
 // Configure 配置 Web
 func (gx *GoX) Configure(configure configure.WebConfigure) *GoX {
+	gx.lazyInit()
 	gx.config(configure)
 	return gx
 }
 
 // ContextPath 设置ContextPath
 func (gx *GoX) ContextPath(contextPath string) *GoX {
-	ctx.C().SetContextPath(contextPath)
+	gx.lazyInit()
+	gx.SetContextPath(contextPath)
 	return gx
 }
 
 // StaticDir 静态资源文件夹
 func (gx *GoX) StaticDir(dir string) *GoX {
-	ctx.C().SetStaticDir(dir)
+	gx.lazyInit()
+	gx.SetStaticDir(dir)
 	return gx
 }
 
 // NotFoundHandler 配置 404 处理器
 func (gx *GoX) NotFoundHandler(handler http.HandlerFunc) *GoX {
-	ctx.C().SetNotFoundHandler(handler)
+	gx.lazyInit()
+	gx.SetNotFoundHandler(handler)
 	return gx
 }
 
 // UnsupportedMethodHandler 配置 方法不支持 处理器
 func (gx *GoX) UnsupportedMethodHandler(handler http.HandlerFunc) *GoX {
-	ctx.C().SetUnSupportMethodHandler(handler)
+	gx.lazyInit()
+	gx.SetUnSupportMethodHandler(handler)
 	return gx
 }
 
 // ErrorCodeHandler 为错误码添加处理器
 func (gx *GoX) ErrorCodeHandler(statusCode int, handler http.HandlerFunc) *GoX {
-	ctx.C().AddErrorHandler(statusCode, handler)
+	gx.lazyInit()
+	gx.AddErrorHandler(statusCode, handler)
+	return gx
+}
+
+// ErrorTypeHandler 为错误类型添加全局的错误处理器
+//
+// target 与 errors.As 的第二个参数相同，如 new(*common.HTTPError)
+func (gx *GoX) ErrorTypeHandler(target interface{}, handler common.ErrorHandlerFunc) *GoX {
+	gx.lazyInit()
+	gx.AddErrorTypeHandler(target, handler)
 	return gx
 }
 
 // ArgumentResolver 参数处理器
 func (gx *GoX) ArgumentResolver(resolver resolver.ArgumentResolver) *GoX {
-	ctx.C().SetArgumentResolver(resolver)
+	gx.lazyInit()
+	gx.SetArgumentResolver(resolver)
+	return gx
+}
+
+// ParamResolver 添加单个参数的处理器
+//
+// 按添加顺序匹配，优先于内置的参数来源，参数仍需在路由上注册，如 Ship.Param("principal")
+func (gx *GoX) ParamResolver(resolvers ...resolver.ParamResolver) *GoX {
+	gx.lazyInit()
+	gx.AddParamResolver(resolvers...)
 	return gx
 }
 
 // ResultResolver 结果处理器
 func (gx *GoX) ResultResolver(resolver resolver.ResultResolver) *GoX {
-	ctx.C().SetResultResolver(resolver)
+	gx.lazyInit()
+	gx.SetResultResolver(resolver)
 	return gx
 }
 
 // ErrorResolver 全局异常处理器
 func (gx *GoX) ErrorResolver(resolver resolver.ErrorResolver) *GoX {
-	ctx.C().SetErrorResolver(resolver)
+	gx.lazyInit()
+	gx.SetErrorResolver(resolver)
+	return gx
+}
+
+// RePanic panic 转为 500 响应后是否继续抛出
+//
+// 开发时开启，便于调试
+func (gx *GoX) RePanic(rePanic bool) *GoX {
+	gx.lazyInit()
+	gx.SetRePanic(rePanic)
+	return gx
+}
+
+// TimeoutStatus 处理超时时响应的状态码，默认为 503，可改为 504
+//
+// 超时时间由路由上的 Ship.Timeout 配置
+func (gx *GoX) TimeoutStatus(status int) *GoX {
+	gx.lazyInit()
+	gx.SetTimeoutStatus(status)
+	return gx
+}
+
+// MaxBodySize 请求体的最大字节数，超过时响应 413
+//
+// 路由上配置的 Ship.MaxBodySize 优先
+func (gx *GoX) MaxBodySize(size int64) *GoX {
+	gx.lazyInit()
+	gx.SetMaxBodySize(size)
+	return gx
+}
+
+// CookieKey cookie 签名和加密的密钥，见 of.Controller 的 cookie 方法
+func (gx *GoX) CookieKey(key []byte) *GoX {
+	gx.lazyInit()
+	gx.SetCookieKey(key)
+	return gx
+}
+
+// PageSize 分页请求的默认每页条数和最大每页条数
+//
+// 默认为 common.DefaultPageSize 和 common.MaxPageSize
+func (gx *GoX) PageSize(size, maxSize int) *GoX {
+	gx.lazyInit()
+	gx.SetPageSize(size, maxSize)
 	return gx
 }
 
 // Mapping 添加 控制器 映射
 func (gx *GoX) Mapping(path string, ctrls ...core.Controller) *GoX {
+	gx.lazyInit()
 	if ctrls == nil || len(ctrls) == 0 {
 		return gx
 	}
@@ -168,11 +348,17 @@ func (gx *GoX) Mapping(path string, ctrls ...core.Controller) *GoX {
 	// 逐个添加
 	for _, ctrl := range ctrls {
 		// 创建一个 处理器映射器对象
-		mapper := core.NewMapper(ctx.C().GetContextPath(), path, ctrl)
+		mapper := core.NewWiresMapper(gx.wires, gx.GetContextPath(), path, ctrl)
 		// 执行每个控制器的 Mapping() 方法，完成 处理器的注册
 		ctrl.Mapping(mapper)
+		// 依赖注入失败时直接终止，避免请求时才发现字段为 nil
+		if _, isStruct, isPtr := util.StructType(ctrl); isStruct && isPtr {
+			if err := gx.provider.Inject(ctrl); err != nil {
+				gog.FatalF("Can not inject the controller [%T]: %v", ctrl, err)
+			}
+		}
 		// 注册到 IOC
-		ioc.C().Single("", ctrl)
+		gx.provider.Single("", ctrl)
 	}
 	return gx
 }
@@ -187,6 +373,11 @@ func (gx *GoX) Run(server *http.Server) {
 		server.Handler = gx
 	}
 
+	// 过滤器的执行顺序
+	for _, info := range gx.Filters() {
+		gog.InfoF("Filter [{}] of [{}] with order [{}]", info.Name, info.Path, info.Order)
+	}
+
 	// 支持优雅关闭服务
 	go gx.Grace(server)
 
@@ -221,12 +412,12 @@ func (gx *GoX) Grace(server *http.Server) {
 func (gx *GoX) config(configure configure.WebConfigure) {
 	if configure != nil {
 		// 配置 Context
-		configure.Context(ctx.C())
+		configure.Context(gx.GoXContext)
 
 		// 注册过滤器
-		configure.ConfigFilter(filterChain)
+		configure.ConfigFilter(gx.filterChain)
 
 		// 注册拦截器
-		configure.ConfigInterceptor(interceptorRegister)
+		configure.ConfigInterceptor(gx.interceptorRegister)
 	}
 }
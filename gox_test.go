@@ -24,8 +24,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/yhyzgn/gox/core"
+	"github.com/yhyzgn/gox/ctx"
+	"github.com/yhyzgn/gox/ioc"
 	"github.com/yhyzgn/gox/util"
+	"github.com/yhyzgn/gox/wire"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -147,3 +151,94 @@ func TestRouter_Add(t *testing.T) {
 	//	fmt.Printf("\nParameter OUT: "+strconv.Itoa(o)+"\nKind: %v\nName: %v\n", return_Kind, returnV.Name())
 	//}
 }
+
+type nameController struct {
+	name string
+}
+
+func (b nameController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/name").HandlerFunc(b.Name).Mapping()
+	mapper.Put("/name").HandlerFunc(b.Name).Mapping()
+}
+
+func (b nameController) Name() string {
+	return b.name
+}
+
+func TestNewGoX_Isolation(t *testing.T) {
+	public := NewGoX().Mapping("/api", nameController{name: "public"})
+	admin := NewGoX().ContextPath("/admin").Mapping("/api", nameController{name: "admin"})
+
+	cases := []struct {
+		server *GoX
+		path   string
+		status int
+		body   string
+	}{
+		{public, "/api/name", http.StatusOK, "public"},
+		{admin, "/admin/api/name", http.StatusOK, "admin"},
+		{public, "/admin/api/name", http.StatusNotFound, ""},
+		{admin, "/api/name", http.StatusNotFound, ""},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		c.server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if recorder.Code != c.status {
+			t.Errorf("request [%v] should response status %d, but %d", c.path, c.status, recorder.Code)
+		}
+		if c.body != "" && recorder.Body.String() != c.body {
+			t.Errorf("request [%v] should response %v, but %v", c.path, c.body, recorder.Body.String())
+		}
+	}
+}
+
+type Greeter interface {
+	Greet(name string) string
+}
+
+type greeter struct {
+	prefix string
+}
+
+func (g *greeter) Greet(name string) string {
+	return g.prefix + name
+}
+
+type GreetController struct {
+	Greeter Greeter `auto:"greeter"`
+}
+
+func (gc *GreetController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/greet").HandlerFunc(gc.Greet).Required("name").Mapping()
+}
+
+func (gc *GreetController) Greet(name string) string {
+	return gc.Greeter.Greet(name)
+}
+
+func TestNewGoX_Provider(t *testing.T) {
+	// 控制器的依赖从服务自己的容器中注入
+	server := NewGoX()
+	server.Provider().Single("greeter", &greeter{prefix: "hi "})
+	server.Mapping("/api", &GreetController{})
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/greet?name=gox", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "hi gox" {
+		t.Errorf("should response %q, but %d %q", "hi gox", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestDefault(t *testing.T) {
+	// 只比较组件，不向全局组件注册任何东西，可重复执行
+	for _, server := range []*GoX{Default(), {}} {
+		// Provider() 之后零值的服务才会使用全局组件
+		if server.Provider() != ioc.C() || server.GoXContext != ctx.C() || server.wires != wire.Instance {
+			t.Error("the default server should use the global components")
+		}
+	}
+	server := NewGoX()
+	if server.GoXContext == ctx.C() || server.Provider() == ioc.C() || server.wires == wire.Instance {
+		t.Error("NewGoX() should not use the global components")
+	}
+}
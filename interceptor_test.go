@@ -0,0 +1,256 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 11:50 上午
+// version: 1.0.0
+// desc   : 拦截器测试
+
+package gox
+
+import (
+	"fmt"
+	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/component/filter"
+	"github.com/yhyzgn/gox/component/interceptor"
+	"github.com/yhyzgn/gox/core"
+	"github.com/yhyzgn/gox/ctx"
+	"github.com/yhyzgn/gox/util"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type csrfInterceptor struct {
+}
+
+func (csrfInterceptor) PreHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler) (bool, *http.Request, http.ResponseWriter) {
+	if request.Header.Get("X-CSRF-Token") == "" {
+		writer.WriteHeader(http.StatusForbidden)
+		return false, request, writer
+	}
+	return true, request, writer
+}
+
+func (csrfInterceptor) AfterHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler, result reflect.Value, err error) (*http.Request, http.ResponseWriter) {
+	return request, writer
+}
+
+type csrfConfigure struct {
+}
+
+func (csrfConfigure) Context(ctx *ctx.GoXContext) {
+	ctx.SetContextPath("/gox")
+}
+
+func (csrfConfigure) ConfigFilter(chain *filter.Chain) {
+}
+
+func (csrfConfigure) ConfigInterceptor(register *interceptor.Register) {
+	register.AddInterceptorsWhen("/admin/**", util.When().Methods(http.MethodPost, http.MethodPut, http.MethodDelete), csrfInterceptor{})
+}
+
+type adminController struct {
+}
+
+func (m adminController) Mapping(mapper *core.Mapper) {
+	mapper.Request("/user").HandlerFunc(m.User).Method(http.MethodGet, http.MethodPost).Mapping()
+}
+
+func (adminController) User() string {
+	return "user"
+}
+
+func TestGoX_InterceptorCondition(t *testing.T) {
+	server := NewGoX().Configure(csrfConfigure{}).Mapping("/admin", adminController{}).Mapping("/api/gox/admin", adminController{})
+
+	cases := []struct {
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{http.MethodGet, "/gox/admin/user", "", http.StatusOK},
+		{http.MethodPost, "/gox/admin/user", "", http.StatusForbidden},
+		{http.MethodPost, "/gox/admin/user", "gox", http.StatusOK},
+		{http.MethodPost, "/gox/api/gox/admin/user", "", http.StatusOK},
+	}
+	for _, c := range cases {
+		request := httptest.NewRequest(c.method, c.path, nil)
+		if c.token != "" {
+			request.Header.Set("X-CSRF-Token", c.token)
+		}
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		if recorder.Code != c.status {
+			t.Errorf("[%v %v] should response status %d, but %d", c.method, c.path, c.status, recorder.Code)
+		}
+	}
+}
+
+type lifecycleInterceptor struct {
+	name    string
+	deny    bool
+	records *[]string
+}
+
+func (li lifecycleInterceptor) PreHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler) (bool, *http.Request, http.ResponseWriter) {
+	*li.records = append(*li.records, "pre:"+li.name)
+	if li.deny {
+		writer.WriteHeader(http.StatusForbidden)
+	}
+	return !li.deny, request, writer
+}
+
+func (li lifecycleInterceptor) PostHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler, result reflect.Value, err error) (reflect.Value, error) {
+	*li.records = append(*li.records, "post:"+li.name)
+	if result.IsValid() && result.Interface() == "ok" {
+		return reflect.ValueOf("changed by " + li.name), err
+	}
+	return result, err
+}
+
+func (li lifecycleInterceptor) AfterHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler, result reflect.Value, err error) (*http.Request, http.ResponseWriter) {
+	*li.records = append(*li.records, fmt.Sprintf("after:%v:%v", li.name, err != nil))
+	return request, writer
+}
+
+func (li lifecycleInterceptor) AfterCompletion(writer http.ResponseWriter, request *http.Request, handler common.Handler, err error) {
+	*li.records = append(*li.records, fmt.Sprintf("done:%v:%T", li.name, err))
+}
+
+type lifecycleController struct {
+	records *[]string
+}
+
+func (n lifecycleController) Mapping(mapper *core.Mapper) {
+	mapper.Use(lifecycleInterceptor{name: "a", records: n.records}, lifecycleInterceptor{name: "b", records: n.records})
+	mapper.Get("/ok").HandlerFunc(n.Ok).Mapping()
+	mapper.Get("/fail").HandlerFunc(n.Fail).Mapping()
+	mapper.Get("/panic").HandlerFunc(n.Panic).Mapping()
+	mapper.Group("/deny", func(group *core.Mapper) {
+		group.Use(lifecycleInterceptor{name: "c", deny: true, records: n.records})
+		group.Get("/ok").HandlerFunc(n.Ok).Mapping()
+	})
+}
+
+func (lifecycleController) Ok() string {
+	return "ok"
+}
+
+func (lifecycleController) Fail() (string, error) {
+	return "", common.Conflict("fail")
+}
+
+func (lifecycleController) Panic() string {
+	panic("boom")
+}
+
+func TestGoX_InterceptorLifecycle(t *testing.T) {
+	records := make([]string, 0)
+	server := NewGoX().Mapping("/api", lifecycleController{records: &records})
+
+	cases := []struct {
+		path    string
+		status  int
+		body    string
+		records []string
+	}{
+		{"/api/ok", http.StatusOK, "changed by b", []string{"pre:a", "pre:b", "post:b", "after:b:false", "post:a", "after:a:false", "done:b:<nil>", "done:a:<nil>"}},
+		{"/api/fail", http.StatusConflict, "", []string{"pre:a", "pre:b", "post:b", "after:b:true", "post:a", "after:a:true", "done:b:*common.HTTPError", "done:a:*common.HTTPError"}},
+		{"/api/panic", http.StatusInternalServerError, "", []string{"pre:a", "pre:b", "done:b:*common.PanicError", "done:a:*common.PanicError"}},
+		{"/api/deny/ok", http.StatusForbidden, "", []string{"pre:a", "pre:b", "pre:c", "done:b:<nil>", "done:a:<nil>"}},
+	}
+	for _, c := range cases {
+		records = records[:0]
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if recorder.Code != c.status {
+			t.Errorf("[%v] should response status %d, but %d", c.path, c.status, recorder.Code)
+		}
+		if c.body != "" && recorder.Body.String() != c.body {
+			t.Errorf("[%v] should response %q, but %q", c.path, c.body, recorder.Body.String())
+		}
+		if !reflect.DeepEqual(records, c.records) {
+			t.Errorf("[%v] should run %v, but %v", c.path, c.records, records)
+		}
+	}
+}
+
+type groupInterceptor struct {
+	name    string
+	records *[]string
+}
+
+func (gi groupInterceptor) PreHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler) (bool, *http.Request, http.ResponseWriter) {
+	*gi.records = append(*gi.records, gi.name)
+	return true, request, writer
+}
+
+func (gi groupInterceptor) AfterHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler, result reflect.Value, err error) (*http.Request, http.ResponseWriter) {
+	return request, writer
+}
+
+type groupController struct {
+	records *[]string
+}
+
+func (q groupController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/root").HandlerFunc(q.Ok).Mapping()
+	mapper.Group("/v1/", func(v1 *core.Mapper) {
+		v1.Use(groupInterceptor{name: "v1", records: q.records})
+		v1.Get("/users").HandlerFunc(q.Ok).Mapping()
+		v1.Group("admin", func(admin *core.Mapper) {
+			admin.Use(groupInterceptor{name: "admin", records: q.records})
+			admin.Get("roles").HandlerFunc(q.Ok).Mapping()
+		})
+		v1.Get("/books").HandlerFunc(q.Ok).Mapping()
+	})
+	mapper.Group("v2", func(v2 *core.Mapper) {
+		v2.Get("/users").HandlerFunc(q.Ok).Mapping()
+	})
+}
+
+func (groupController) Ok() string {
+	return "ok"
+}
+
+func TestMapper_Group(t *testing.T) {
+	records := make([]string, 0)
+	server := NewGoX().Mapping("/api", groupController{records: &records})
+
+	cases := []struct {
+		path    string
+		records []string
+	}{
+		{"/api/root", []string{}},
+		{"/api/v1/users", []string{"v1"}},
+		{"/api/v1/admin/roles", []string{"v1", "admin"}},
+		{"/api/v1/books", []string{"v1"}},
+		{"/api/v2/users", []string{}},
+	}
+	for _, c := range cases {
+		records = records[:0]
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("[%v] should be mapped, but %d", c.path, recorder.Code)
+		}
+		if !reflect.DeepEqual(records, c.records) {
+			t.Errorf("[%v] should pass by interceptors %v, but %v", c.path, c.records, records)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"github.com/yhyzgn/gox/util"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -113,14 +114,37 @@ func (c *Container) GetByTypeSingle(tp reflect.Type) (interface{}, error) {
 		return c.singles[tp.String()], nil
 	}
 	// 查找单例
-	for _, item := range c.singles {
-		if tp == reflect.TypeOf(item) {
-			return item, nil
+	if item, err := c.singleBy(tp, func(itemType reflect.Type) bool { return itemType == tp }); item != nil || err != nil {
+		return item, err
+	}
+	// 接口类型，查找其实现
+	if tp.Kind() == reflect.Interface {
+		if item, err := c.singleBy(tp, func(itemType reflect.Type) bool { return itemType.Implements(tp) }); item != nil || err != nil {
+			return item, err
 		}
 	}
 	return nil, errors.New("ioc type '" + tp.String() + "' dependency not found")
 }
 
+// singleBy 查找唯一满足条件的单例
+//
+// 有多个单例满足条件时无法确定使用哪一个，返回错误，需改为按名称注入
+func (c *Container) singleBy(tp reflect.Type, matches func(itemType reflect.Type) bool) (interface{}, error) {
+	var found interface{}
+	names := make([]string, 0)
+	for name, item := range c.singles {
+		if matches(reflect.TypeOf(item)) {
+			found = item
+			names = append(names, name)
+		}
+	}
+	if len(names) > 1 {
+		sort.Strings(names)
+		return nil, fmt.Errorf("ioc type '%v' is ambiguous, found beans %v, please inject by name, such as `auto:\"%v\"`", tp, names, names[0])
+	}
+	return found, nil
+}
+
 func (c *Container) GetByTypePrototype(tp reflect.Type) (interface{}, error) {
 	// 先查找注册为空名称的bean
 	factory := c.prototypes[tp.String()]
@@ -65,6 +65,11 @@ func (p *Provider) GetSingleByType(tp reflect.Type) interface{} {
 	return iv
 }
 
+// LookupSingleByType 按类型查找单例，未找到或找到多个时返回错误
+func (p *Provider) LookupSingleByType(tp reflect.Type) (interface{}, error) {
+	return p.container.GetByTypeSingle(tp)
+}
+
 func (p *Provider) GetPrototypeByType(tp reflect.Type) interface{} {
 	iv, _ := p.container.GetByTypePrototype(tp)
 	return iv
@@ -23,6 +23,8 @@ package ioc
 import (
 	"fmt"
 	"github.com/yhyzgn/gox/util"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -65,3 +67,41 @@ func TestNewProvider(t *testing.T) {
 
 	fmt.Println(util.StructType(demo))
 }
+
+type Named interface {
+	Name() string
+}
+
+func (a *A) Name() string {
+	return a.Info
+}
+
+func (b *B) Name() string {
+	return b.Info
+}
+
+type NamedDemo struct {
+	Named Named `auto:""`
+}
+
+func TestProvider_GetByTypeAmbiguous(t *testing.T) {
+	provider := NewProvider().Single("a", &A{Info: "AA"})
+
+	// 只有一个实现时按类型注入
+	demo := &NamedDemo{}
+	if err := provider.Inject(demo); err != nil || demo.Named.Name() != "AA" {
+		t.Fatalf("the only implementation should be injected, but %v", err)
+	}
+
+	// 有多个实现时无法确定注入哪一个
+	provider.Single("b", &B{Info: "BB"})
+	if err := provider.Inject(&NamedDemo{}); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Fatalf("ambiguous implementations should not be injected, but %v", err)
+	}
+	if _, err := provider.LookupSingleByType(reflect.TypeOf((*Named)(nil)).Elem()); err == nil {
+		t.Fatal("ambiguous implementations should be reported")
+	}
+	if bean := provider.GetSingleByType(reflect.TypeOf(&B{})); bean == nil {
+		t.Fatal("the concrete type should still be found")
+	}
+}
@@ -22,8 +22,10 @@ package of
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/ctx"
 	"github.com/yhyzgn/gox/util"
 )
 
@@ -39,3 +41,73 @@ func (c Controller) SetReqAttr(req *http.Request, key common.AttributeKey, value
 func (c Controller) GetReqAttr(req *http.Request, key common.AttributeKey) interface{} {
 	return util.GetRequestAttribute(req, key)
 }
+
+// SetCookie 设置 cookie，path 为空时为 /
+//
+// 在副本上设置默认值，不会修改传入的 cookie
+func (c Controller) SetCookie(writer http.ResponseWriter, cookie *http.Cookie) {
+	copied := *cookie
+	if copied.Path == "" {
+		copied.Path = "/"
+	}
+	http.SetCookie(writer, &copied)
+}
+
+// ClearCookie 清除 cookie，path 需要与设置时一致，为空时为 /
+func (c Controller) ClearCookie(writer http.ResponseWriter, name, path string) {
+	if path == "" {
+		path = "/"
+	}
+	http.SetCookie(writer, &http.Cookie{
+		Name:    name,
+		Path:    path,
+		MaxAge:  -1,
+		Expires: time.Unix(0, 0),
+	})
+}
+
+// SetSignedCookie 设置签名的 cookie，客户端可读但不可篡改
+//
+// 使用 GoX.CookieKey 配置的密钥
+func (c Controller) SetSignedCookie(writer http.ResponseWriter, request *http.Request, cookie *http.Cookie) error {
+	value, err := util.SignCookieValue(ctx.FromRequest(request).GetCookieKey(), cookie.Name, cookie.Value)
+	if err != nil {
+		return err
+	}
+	signed := *cookie
+	signed.Value = value
+	c.SetCookie(writer, &signed)
+	return nil
+}
+
+// GetSignedCookie 获取签名的 cookie 值，签名不匹配时返回 util.ErrInvalidCookie
+func (c Controller) GetSignedCookie(request *http.Request, name string) (string, error) {
+	cookie, err := request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return util.VerifyCookieValue(ctx.FromRequest(request).GetCookieKey(), name, cookie.Value)
+}
+
+// SetEncryptedCookie 设置加密的 cookie，客户端不可读也不可篡改
+//
+// 使用 GoX.CookieKey 配置的密钥
+func (c Controller) SetEncryptedCookie(writer http.ResponseWriter, request *http.Request, cookie *http.Cookie) error {
+	value, err := util.EncryptCookieValue(ctx.FromRequest(request).GetCookieKey(), cookie.Name, cookie.Value)
+	if err != nil {
+		return err
+	}
+	encrypted := *cookie
+	encrypted.Value = value
+	c.SetCookie(writer, &encrypted)
+	return nil
+}
+
+// GetEncryptedCookie 获取加密的 cookie 值，无法解密时返回 util.ErrInvalidCookie
+func (c Controller) GetEncryptedCookie(request *http.Request, name string) (string, error) {
+	cookie, err := request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return util.DecryptCookieValue(ctx.FromRequest(request).GetCookieKey(), name, cookie.Value)
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 11:40 上午
+// version: 1.0.0
+// desc   : Controller基类测试
+
+package of
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestController_SetCookie(t *testing.T) {
+	cookie := &http.Cookie{Name: "sid", Value: "12"}
+	recorder := httptest.NewRecorder()
+	Controller{}.SetCookie(recorder, cookie)
+
+	// 默认值只设置到响应中，不修改传入的 cookie
+	if cookie.Path != "" {
+		t.Errorf("the cookie should not be modified, but path [%v]", cookie.Path)
+	}
+	if cookies := recorder.Result().Cookies(); len(cookies) != 1 || cookies[0].Path != "/" {
+		t.Errorf("the cookie should be set with path [/], but %v", cookies)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 11:50 上午
+// version: 1.0.0
+// desc   : IOC、参数处理器、分页和 cookie 参数测试
+
+package gox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/component/filter"
+	"github.com/yhyzgn/gox/component/interceptor"
+	"github.com/yhyzgn/gox/core"
+	"github.com/yhyzgn/gox/ctx"
+	"github.com/yhyzgn/gox/of"
+	"github.com/yhyzgn/gox/util"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type userInterceptor struct {
+}
+
+func (userInterceptor) PreHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler) (bool, *http.Request, http.ResponseWriter) {
+	return true, util.SetRequestAttribute(request, "user", "gox"), writer
+}
+
+func (userInterceptor) AfterHandle(writer http.ResponseWriter, request *http.Request, handler common.Handler, result reflect.Value, err error) (*http.Request, http.ResponseWriter) {
+	return request, writer
+}
+
+type beanConfigure struct {
+}
+
+func (beanConfigure) Context(ctx *ctx.GoXContext) {
+}
+
+func (beanConfigure) ConfigFilter(chain *filter.Chain) {
+}
+
+func (beanConfigure) ConfigInterceptor(register *interceptor.Register) {
+	register.AddInterceptors("/api/**", userInterceptor{})
+}
+
+type beanController struct {
+}
+
+func (i beanController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/type").HandlerFunc(i.Greet).Bean("").Attribute("user").Mapping()
+	mapper.Get("/name").HandlerFunc(i.Greet).Bean("greeter").Attribute("user").Mapping()
+	mapper.Get("/missing").HandlerFunc(i.Greet).Bean("missing").Attribute("user").Mapping()
+}
+
+func (beanController) Greet(greeter Greeter, user string) string {
+	return greeter.Greet(user)
+}
+
+func TestGoX_Bean(t *testing.T) {
+	server := NewGoX()
+	server.Provider().Single("greeter", &greeter{prefix: "hello "})
+	server.Mapping("/api", beanController{})
+	server.Configure(beanConfigure{})
+
+	cases := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/api/type", http.StatusOK, "hello gox"},
+		{"/api/name", http.StatusOK, "hello gox"},
+		{"/api/missing", http.StatusInternalServerError, ""},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if recorder.Code != c.status {
+			t.Errorf("[%v] should response status %d, but %d", c.path, c.status, recorder.Code)
+		}
+		if c.body != "" && recorder.Body.String() != c.body {
+			t.Errorf("[%v] should response %q, but %q", c.path, c.body, recorder.Body.String())
+		}
+	}
+}
+
+type Locale string
+
+type localeResolver struct {
+}
+
+func (localeResolver) Supports(param *common.Param) bool {
+	return param.RealType == reflect.TypeOf(Locale(""))
+}
+
+func (localeResolver) Resolve(param *common.Param, writer http.ResponseWriter, request *http.Request) (reflect.Value, error) {
+	lang := request.Header.Get("Accept-Language")
+	if lang == "xx" {
+		return reflect.Value{}, errors.New("unknown language")
+	}
+	if lang == "" {
+		return reflect.Value{}, nil
+	}
+	return reflect.ValueOf(Locale(lang)), nil
+}
+
+type localeController struct {
+}
+
+func (j localeController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/hello").HandlerFunc(j.Hello).Param("locale").Required("name").Mapping()
+}
+
+func (localeController) Hello(locale Locale, name string) string {
+	return fmt.Sprintf("%v:%v", locale, name)
+}
+
+func TestGoX_ParamResolver(t *testing.T) {
+	server := NewGoX().ParamResolver(localeResolver{}).Mapping("/api", localeController{})
+
+	cases := []struct {
+		lang   string
+		status int
+		body   string
+	}{
+		{"zh-CN", http.StatusOK, "zh-CN:gox"},
+		{"", http.StatusOK, ":gox"},
+		{"xx", http.StatusBadRequest, ""},
+	}
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodGet, "/api/hello?name=gox", nil)
+		request.Header.Set("Accept-Language", c.lang)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		if recorder.Code != c.status {
+			t.Errorf("[%v] should response status %d, but %d", c.lang, c.status, recorder.Code)
+		}
+		if c.body != "" && recorder.Body.String() != c.body {
+			t.Errorf("[%v] should response %q, but %q", c.lang, c.body, recorder.Body.String())
+		}
+	}
+}
+
+type pageController struct {
+}
+
+func (k pageController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/books").HandlerFunc(k.Books).Param("title").Mapping()
+}
+
+func (pageController) Books(title string, pageable *common.Pageable) *common.Page {
+	books := make([]Book, 0, pageable.Size)
+	for i := pageable.Offset(); i < pageable.Offset()+pageable.Size && i < 45; i++ {
+		books = append(books, Book{Title: title, Price: i})
+	}
+	return common.NewPage(books, *pageable, 45)
+}
+
+func TestGoX_Pageable(t *testing.T) {
+	server := NewGoX().PageSize(10, 20).Mapping("/api", pageController{})
+
+	cases := []struct {
+		query  string
+		status int
+		size   int
+		sort   []common.Order
+		link   string
+	}{
+		{"title=gox", http.StatusOK, 10, nil, `</api/books?page=1&size=10&title=gox>; rel="first", </api/books?page=2&size=10&title=gox>; rel="next", </api/books?page=5&size=10&title=gox>; rel="last"`},
+		{"page=3&size=50&sort=price,desc&sort=title", http.StatusOK, 20, []common.Order{{Property: "price", Desc: true}, {Property: "title"}}, `</api/books?page=1&size=20&sort=price%2Cdesc&sort=title>; rel="first", </api/books?page=2&size=20&sort=price%2Cdesc&sort=title>; rel="prev", </api/books?page=3&size=20&sort=price%2Cdesc&sort=title>; rel="last"`},
+		{"page=0", http.StatusBadRequest, 0, nil, ""},
+		{"sort=,desc", http.StatusBadRequest, 0, nil, ""},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/books?"+c.query, nil))
+		if recorder.Code != c.status {
+			t.Errorf("[%v] should response status %d, but %d", c.query, c.status, recorder.Code)
+			continue
+		}
+		if c.status != http.StatusOK {
+			continue
+		}
+		if total := recorder.Header().Get("X-Total-Count"); total != "45" {
+			t.Errorf("[%v] should response X-Total-Count 45, but %v", c.query, total)
+		}
+		if link := recorder.Header().Get("Link"); link != c.link {
+			t.Errorf("[%v] should response Link %v, but %v", c.query, c.link, link)
+		}
+		var page struct {
+			Content []Book         `json:"content"`
+			Size    int            `json:"size"`
+			Sort    []common.Order `json:"sort"`
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+		if page.Size != c.size || !reflect.DeepEqual(page.Sort, c.sort) {
+			t.Errorf("[%v] should response size %d and sort %v, but %d and %v", c.query, c.size, c.sort, page.Size, page.Sort)
+		}
+	}
+}
+
+type cookieController struct {
+	of.Controller
+}
+
+func (l cookieController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/cookie").HandlerFunc(l.Cookie).Cookie("sid").OptionalCookie("theme").Mapping()
+	mapper.Post("/login").HandlerFunc(l.Login).Mapping()
+	mapper.Get("/me").HandlerFunc(l.Me).Mapping()
+}
+
+func (cookieController) Cookie(sid int, theme *http.Cookie) string {
+	if theme == nil {
+		return fmt.Sprintf("%d", sid)
+	}
+	return fmt.Sprintf("%d:%v", sid, theme.Value)
+}
+
+func (l cookieController) Login(writer http.ResponseWriter, request *http.Request) error {
+	if err := l.SetSignedCookie(writer, request, &http.Cookie{Name: "uid", Value: "12"}); err != nil {
+		return err
+	}
+	if err := l.SetEncryptedCookie(writer, request, &http.Cookie{Name: "name", Value: "gox"}); err != nil {
+		return err
+	}
+	l.ClearCookie(writer, "sid", "")
+	return nil
+}
+
+func (l cookieController) Me(request *http.Request) (string, error) {
+	uid, err := l.GetSignedCookie(request, "uid")
+	if err != nil {
+		return "", common.WrapHTTPError(http.StatusUnauthorized, err)
+	}
+	name, err := l.GetEncryptedCookie(request, "name")
+	if err != nil {
+		return "", common.WrapHTTPError(http.StatusUnauthorized, err)
+	}
+	return uid + ":" + name, nil
+}
+
+func TestGoX_Cookie(t *testing.T) {
+	server := NewGoX().CookieKey([]byte("secret")).Mapping("/api", cookieController{})
+
+	cases := []struct {
+		cookies []*http.Cookie
+		status  int
+		body    string
+	}{
+		{[]*http.Cookie{{Name: "sid", Value: "12"}}, http.StatusOK, "12"},
+		{[]*http.Cookie{{Name: "sid", Value: "12"}, {Name: "theme", Value: "dark"}}, http.StatusOK, "12:dark"},
+		{[]*http.Cookie{{Name: "sid", Value: "abc"}}, http.StatusBadRequest, ""},
+		{nil, http.StatusBadRequest, ""},
+	}
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodGet, "/api/cookie", nil)
+		for _, cookie := range c.cookies {
+			request.AddCookie(cookie)
+		}
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		if recorder.Code != c.status {
+			t.Errorf("%v should response status %d, but %d", c.cookies, c.status, recorder.Code)
+		}
+		if c.body != "" && recorder.Body.String() != c.body {
+			t.Errorf("%v should response %q, but %q", c.cookies, c.body, recorder.Body.String())
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/api/login", nil))
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("[/api/login] should response status %d, but %d", http.StatusNoContent, recorder.Code)
+	}
+	cookies := recorder.Result().Cookies()
+	if len(cookies) != 3 || cookies[2].MaxAge != -1 {
+		t.Fatalf("should set signed, encrypted and cleared cookies, but %v", cookies)
+	}
+	if cookies[1].Value == "gox" {
+		t.Error("cookie [name] should be encrypted")
+	}
+
+	request := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	request.AddCookie(cookies[0])
+	request.AddCookie(cookies[1])
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	if recorder.Body.String() != "12:gox" {
+		t.Errorf("[/api/me] should response %q, but %q", "12:gox", recorder.Body.String())
+	}
+
+	// 篡改签名的 cookie
+	request = httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	request.AddCookie(&http.Cookie{Name: "uid", Value: "MTM." + strings.SplitN(cookies[0].Value, ".", 2)[1]})
+	request.AddCookie(cookies[1])
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusUnauthorized {
+		t.Errorf("tampered cookie should response status %d, but %d", http.StatusUnauthorized, recorder.Code)
+	}
+}
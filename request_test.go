@@ -0,0 +1,297 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 11:50 上午
+// version: 1.0.0
+// desc   : 请求的匹配、参数、超时和 panic 测试
+
+package gox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/component/filter"
+	"github.com/yhyzgn/gox/component/interceptor"
+	"github.com/yhyzgn/gox/core"
+	"github.com/yhyzgn/gox/ctx"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoX_Methods(t *testing.T) {
+	server := NewGoX().Mapping("/api", nameController{name: "methods"})
+
+	cases := []struct {
+		method string
+		status int
+		allow  string
+		body   string
+	}{
+		{http.MethodGet, http.StatusOK, "", "methods"},
+		{http.MethodPut, http.StatusOK, "", "methods"},
+		{http.MethodHead, http.StatusOK, "", ""},
+		{http.MethodOptions, http.StatusNoContent, "GET, HEAD, PUT, OPTIONS", ""},
+		{http.MethodPost, http.StatusMethodNotAllowed, "GET, HEAD, PUT, OPTIONS", ""},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(c.method, "/api/name", nil))
+		if recorder.Code != c.status {
+			t.Errorf("method [%v] should response status %d, but %d", c.method, c.status, recorder.Code)
+		}
+		if allow := recorder.Header().Get("Allow"); allow != c.allow {
+			t.Errorf("method [%v] should response Allow [%v], but [%v]", c.method, c.allow, allow)
+		}
+		if c.status == http.StatusOK && recorder.Body.String() != c.body {
+			t.Errorf("method [%v] should response %v, but %v", c.method, c.body, recorder.Body.String())
+		}
+	}
+}
+
+type Book struct {
+	Title string `json:"title" form:"title"`
+	Price int    `json:"price" form:"price"`
+}
+
+type bookController struct {
+}
+
+func (c bookController) Mapping(mapper *core.Mapper) {
+	mapper.Post("/book").HandlerFunc(c.Book).Body("book").Consumes("application/json", "application/x-www-form-urlencoded").Mapping()
+	mapper.Patch("/book").HandlerFunc(c.Book).Body("book").MaxBodySize(32).Mapping()
+	mapper.Delete("/book").HandlerFunc(c.Book).Body("book").Mapping()
+	mapper.Put("/raw").HandlerFunc(c.Raw).Body("reader").Mapping()
+	mapper.Put("/stream").HandlerFunc(c.Stream).Body("decoder").Mapping()
+}
+
+func (bookController) Raw(reader io.Reader) (string, error) {
+	bs, err := ioutil.ReadAll(reader)
+	return string(bs), err
+}
+
+func (bookController) Stream(decoder *json.Decoder) (int, error) {
+	count := 0
+	for decoder.More() {
+		book := new(Book)
+		if err := decoder.Decode(book); err != nil {
+			return 0, err
+		}
+		count += book.Price
+	}
+	return count, nil
+}
+
+func (bookController) Book(book *Book) string {
+	return fmt.Sprintf("%s:%d", book.Title, book.Price)
+}
+
+func TestGoX_Body(t *testing.T) {
+	server := NewGoX().Mapping("/api", bookController{})
+
+	cases := []struct {
+		contentType string
+		body        string
+		status      int
+		response    string
+	}{
+		{"application/json", `{"title":"gox","price":12}`, http.StatusOK, "gox:12"},
+		{"application/x-www-form-urlencoded", "title=gox&price=12", http.StatusOK, "gox:12"},
+		{"application/json", `{"title":`, http.StatusBadRequest, ""},
+		{"application/x-www-form-urlencoded", "price=abc", http.StatusBadRequest, ""},
+		{"application/xml", "<Book></Book>", http.StatusUnsupportedMediaType, ""},
+		{"application/json", "", http.StatusBadRequest, ""},
+	}
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodPost, "/api/book", strings.NewReader(c.body))
+		request.Header.Set("Content-Type", c.contentType)
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		if recorder.Code != c.status {
+			t.Errorf("body [%v] of [%v] should response status %d, but %d", c.body, c.contentType, c.status, recorder.Code)
+		}
+		if c.response != "" && recorder.Body.String() != c.response {
+			t.Errorf("body [%v] of [%v] should response %v, but %v", c.body, c.contentType, c.response, recorder.Body.String())
+		}
+	}
+}
+
+func TestGoX_BodyMethods(t *testing.T) {
+	server := NewGoX().MaxBodySize(64).Mapping("/api", bookController{})
+
+	cases := []struct {
+		method   string
+		path     string
+		body     string
+		status   int
+		response string
+	}{
+		{http.MethodPatch, "/api/book", `{"title":"gox","price":12}`, http.StatusOK, "gox:12"},
+		{http.MethodPatch, "/api/book", `{"title":"gox","price":12,"extra":1}`, http.StatusRequestEntityTooLarge, ""},
+		{http.MethodDelete, "/api/book", `{"title":"gox","price":12}`, http.StatusOK, "gox:12"},
+		{http.MethodDelete, "/api/book", `{"title":"` + strings.Repeat("x", 64) + `"}`, http.StatusRequestEntityTooLarge, ""},
+		{http.MethodPut, "/api/raw", "raw body", http.StatusOK, "raw body"},
+		{http.MethodPut, "/api/stream", `{"price":1} {"price":2}`, http.StatusOK, "3"},
+	}
+	for _, c := range cases {
+		request := httptest.NewRequest(c.method, c.path, strings.NewReader(c.body))
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		if recorder.Code != c.status {
+			t.Errorf("[%v %v] should response status %d, but %d", c.method, c.path, c.status, recorder.Code)
+		}
+		if c.response != "" && recorder.Body.String() != c.response {
+			t.Errorf("[%v %v] should response %v, but %v", c.method, c.path, c.response, recorder.Body.String())
+		}
+	}
+
+	// 未知长度的请求体，读取时才发现超过限制
+	request := httptest.NewRequest(http.MethodDelete, "/api/book", strings.NewReader(`{"title":"`+strings.Repeat("x", 64)+`"}`))
+	request.ContentLength = -1
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("streamed body should response status 413, but %d", recorder.Code)
+	}
+}
+
+type contextController struct {
+}
+
+func (h contextController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/ctx").HandlerFunc(h.Context).Required("name").Mapping()
+	mapper.Get("/slow").HandlerFunc(h.Slow).Timeout(20 * time.Millisecond).Mapping()
+}
+
+func (contextController) Context(writer http.ResponseWriter, c context.Context, name string) string {
+	return fmt.Sprintf("%v:%v", name, c != nil && writer != nil)
+}
+
+func (contextController) Slow(c context.Context) string {
+	<-c.Done()
+	return "late"
+}
+
+func TestGoX_Context(t *testing.T) {
+	server := NewGoX().Mapping("/api", contextController{})
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/ctx?name=gox", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "gox:true" {
+		t.Errorf("[/api/ctx] should response %q, but %d %q", "gox:true", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+	if recorder.Code != http.StatusServiceUnavailable || strings.Contains(recorder.Body.String(), "late") {
+		t.Errorf("[/api/slow] should response status %d, but %d %q", http.StatusServiceUnavailable, recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	server.TimeoutStatus(http.StatusGatewayTimeout).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+	if recorder.Code != http.StatusGatewayTimeout {
+		t.Errorf("[/api/slow] should response status %d, but %d", http.StatusGatewayTimeout, recorder.Code)
+	}
+
+	// 客户端已断开，不再响应
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/ctx?name=gox", nil).WithContext(c))
+	if recorder.Body.Len() != 0 {
+		t.Errorf("[/api/ctx] should not response after the client gone, but %q", recorder.Body.String())
+	}
+}
+
+type panicController struct {
+}
+
+func (d panicController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/panic").HandlerFunc(d.Panic).Mapping()
+	mapper.Get("/panic/timeout").HandlerFunc(d.PanicTimeout).Timeout(time.Second).Mapping()
+}
+
+func (panicController) Panic() string {
+	panic("boom")
+}
+
+func (panicController) PanicTimeout(writer http.ResponseWriter) string {
+	writer.Header().Set("X-Trace", "panic")
+	panic("boom")
+}
+
+type panicFilter struct {
+}
+
+func (panicFilter) DoFilter(writer http.ResponseWriter, request *http.Request, chain *filter.Chain) {
+	panic("filter boom")
+}
+
+type panicConfigure struct {
+}
+
+func (panicConfigure) Context(ctx *ctx.GoXContext) {
+}
+
+func (panicConfigure) ConfigFilter(chain *filter.Chain) {
+	chain.AddFilters("/api/filter", panicFilter{})
+}
+
+func (panicConfigure) ConfigInterceptor(register *interceptor.Register) {
+}
+
+func TestGoX_RecoverPanic(t *testing.T) {
+	server := NewGoX().Configure(panicConfigure{}).Mapping("/api", panicController{})
+
+	for _, path := range []string{"/api/panic", "/api/filter"} {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != http.StatusInternalServerError {
+			t.Errorf("panic of [%v] should response status 500, but %d", path, recorder.Code)
+		}
+		if strings.Contains(recorder.Body.String(), "boom") {
+			t.Errorf("panic of [%v] should not be exposed, but %v", path, recorder.Body.String())
+		}
+	}
+
+	// 配置了超时时间时，通过超时响应器响应，处理器设置的 header 不会丢失
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/panic/timeout", nil))
+	if recorder.Code != http.StatusInternalServerError || recorder.Header().Get("X-Trace") != "panic" {
+		t.Errorf("panic with timeout should response status 500 with header, but %d %v", recorder.Code, recorder.Header())
+	}
+
+	server.RePanic(true)
+	recorder = httptest.NewRecorder()
+	func() {
+		defer func() {
+			pe, ok := recover().(*common.PanicError)
+			if !ok || pe.Value != "boom" || pe.Route != "/api/panic" {
+				t.Errorf("panic should be thrown again with route, but %v", pe)
+			}
+		}()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/panic", nil))
+	}()
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("panic should response status 500 before thrown again, but %d", recorder.Code)
+	}
+}
@@ -21,8 +21,17 @@
 package resolver
 
 import (
-	"github.com/yhyzgn/gox/util"
+	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/util"
+)
+
+const (
+	// ContentTypeProblem RFC 7807 问题详情的 Content-Type
+	ContentTypeProblem = "application/problem+json;charset=utf-8"
 )
 
 // ErrorResolver 异常处理器
@@ -33,13 +42,39 @@ type ErrorResolver interface {
 }
 
 // SimpleErrorResolver 默认的异常处理器
+//
+// 以 RFC 7807 application/problem+json 格式响应
 type SimpleErrorResolver struct{}
 
+// NewSimpleErrorResolver 创建新的异常处理器
 func NewSimpleErrorResolver() *SimpleErrorResolver {
 	return new(SimpleErrorResolver)
 }
 
+// Resolve 处理异常
+//
+// 错误链中有 *common.HTTPError 时，使用其状态码、响应头和描述，否则使用 status
 func (ser *SimpleErrorResolver) Resolve(status int, err error, writer http.ResponseWriter) interface{} {
-	util.ResponseJSONStatus(status, writer, err.Error())
+	var he *common.HTTPError
+	if !errors.As(err, &he) {
+		he = common.WrapHTTPError(status, err)
+	}
+	if he.Status <= 0 {
+		// 可能是全局共用的异常，修改副本
+		copied := *he
+		copied.Status = status
+		he = &copied
+	}
+
+	for key, values := range he.Header {
+		writer.Header()[key] = values
+	}
+	util.SetResponseWriterHeader(writer, "Content-Type", ContentTypeProblem)
+
+	bs, e := json.Marshal(he.Problem())
+	if e != nil {
+		bs = []byte(e.Error())
+	}
+	_ = util.ResponseBytes(he.Status, writer, bs)
 	return nil
 }
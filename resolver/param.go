@@ -0,0 +1,51 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 10:12 上午
+// version: 1.0.0
+// desc   : 单个参数的处理器
+
+package resolver
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/yhyzgn/gox/common"
+)
+
+// ParamResolver 单个参数的处理器
+//
+// 在内置的参数来源之前按注册顺序匹配，用于注入 Principal、Pageable、Locale 等自定义类型
+type ParamResolver interface {
+	// Supports 是否支持该参数
+	Supports(param *common.Param) bool
+
+	// Resolve 处理参数，返回值需要能赋值给 param.RealType，无效值时为零值
+	//
+	// 返回 *common.HTTPError 时使用其状态码，其他错误响应 400
+	Resolve(param *common.Param, writer http.ResponseWriter, request *http.Request) (reflect.Value, error)
+}
+
+// MatchParamResolver 匹配第一个支持该参数的处理器
+func MatchParamResolver(param *common.Param, resolvers []ParamResolver) ParamResolver {
+	for _, resolver := range resolvers {
+		if resolver.Supports(param) {
+			return resolver
+		}
+	}
+	return nil
+}
@@ -21,23 +21,37 @@
 package resolver
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 
+	"github.com/yhyzgn/gox/codec"
+	"github.com/yhyzgn/gox/common"
 	"github.com/yhyzgn/gox/util"
 	"github.com/yhyzgn/gox/wire"
 )
 
+const (
+	mediaTypeOctetStream = "application/octet-stream" // 二进制流
+)
+
 // ResultResolver 结果处理器
 type ResultResolver interface {
 	// Resolve 处理结果集
 	Resolve(hw *wire.HandlerWire, values []reflect.Value, writer http.ResponseWriter, request *http.Request) (reflect.Value, error)
 
 	// Response 响应结果
-	Response(value reflect.Value, writer http.ResponseWriter)
+	//
+	// 按请求的 Accept 请求头和路由的 Produces 协商响应格式
+	Response(hw *wire.HandlerWire, value reflect.Value, writer http.ResponseWriter, request *http.Request) error
 }
 
 // SimpleResultResolver 默认的结果处理器
@@ -68,7 +82,10 @@ func (srr *SimpleResultResolver) Resolve(hw *wire.HandlerWire, values []reflect.
 	if ln == 1 {
 		temp := values[0]
 		if temp.Type().Name() == "error" {
-			err = temp.Interface().(error)
+			// 只返回 error 且为 nil 时，没有响应结果
+			if !temp.IsNil() {
+				err = temp.Interface().(error)
+			}
 		} else {
 			value = temp
 		}
@@ -92,6 +109,211 @@ func (srr *SimpleResultResolver) Resolve(hw *wire.HandlerWire, values []reflect.
 }
 
 // Response 响应结果
-func (srr *SimpleResultResolver) Response(value reflect.Value, writer http.ResponseWriter) {
-	util.ResponseJSON(writer, value.Interface())
+//
+// 支持的响应类型：
+//
+//	nil                     -> 204
+//	*common.ResponseEntity  -> 按其状态码、响应头和 cookie 响应，body 按以下规则响应
+//	*common.Redirect        -> 重定向
+//	*common.Stream          -> 流式响应
+//	*common.File            -> 文件下载
+//	*common.Page            -> 设置 X-Total-Count 和 Link 响应头，按 Accept 请求头选择编码器
+//	[]byte                  -> application/octet-stream
+//	string                  -> text/plain
+//	其他                    -> 按 Accept 请求头选择编码器
+//
+// 路由配置了 Produces 或者 Accept 请求头不接受时，[]byte 和 string 也按 Accept 请求头编码
+// 没有满足的编码器时返回 codec.ErrNotAcceptable
+func (srr *SimpleResultResolver) Response(hw *wire.HandlerWire, value reflect.Value, writer http.ResponseWriter, request *http.Request) error {
+	var body interface{}
+	if value.IsValid() && !isNil(value) {
+		body = value.Interface()
+	}
+
+	status := http.StatusOK
+	switch entity := body.(type) {
+	case common.ResponseEntity:
+		body, status = entity.Body, applyEntity(&entity, writer)
+	case *common.ResponseEntity:
+		body, status = entity.Body, applyEntity(entity, writer)
+	}
+	return srr.write(hw, status, body, writer, request)
+}
+
+// write 按响应类型响应
+func (srr *SimpleResultResolver) write(hw *wire.HandlerWire, status int, body interface{}, writer http.ResponseWriter, request *http.Request) error {
+	accept := request.Header.Get("Accept")
+	raw := len(hw.Produces) == 0
+
+	switch v := body.(type) {
+	case nil:
+		if status == http.StatusOK {
+			status = http.StatusNoContent
+		}
+		writer.WriteHeader(status)
+		return nil
+	case *common.Redirect:
+		code := v.Status
+		if code == 0 {
+			code = http.StatusFound
+		}
+		http.Redirect(writer, request, v.Location, code)
+		return nil
+	case *common.Stream:
+		contentType := v.ContentType
+		if contentType == "" {
+			contentType = mediaTypeOctetStream
+		}
+		return writeStream(status, contentType, v.Reader, writer)
+	case *common.File:
+		return writeFile(status, v, writer, request)
+	case *common.Page:
+		applyPage(v, writer, request)
+	case common.Page:
+		applyPage(&v, writer, request)
+	case []byte:
+		if raw && codec.Accepts(accept, mediaTypeOctetStream) {
+			util.SetResponseWriterHeader(writer, "Content-Type", mediaTypeOctetStream)
+			return util.ResponseBytes(status, writer, v)
+		}
+	case string:
+		if raw && codec.Accepts(accept, codec.MediaTypeText) {
+			util.SetResponseWriterHeader(writer, "Content-Type", codec.MediaTypeText+";charset=utf-8")
+			return util.ResponseBytes(status, writer, []byte(v))
+		}
+	}
+
+	encoder, bs, err := encode(body, accept, hw.Produces)
+	if err != nil {
+		return err
+	}
+
+	// 响应格式随 Accept 请求头变化
+	if len(hw.Produces) != 1 {
+		writer.Header().Add("Vary", "Accept")
+	}
+	util.SetResponseWriterHeader(writer, "Content-Type", encoder.ContentType())
+	return util.ResponseBytes(status, writer, bs)
+}
+
+// encode 按协商的顺序编码响应结果
+//
+// 只尝试路由可响应且 Accept 请求头接受的媒体类型，编码失败时（如 xml 不支持 map）尝试下一个，都失败时返回编码错误
+func encode(value interface{}, accept string, produces []string) (codec.Encoder, []byte, error) {
+	mediaTypes := codec.Acceptable(accept, produces...)
+	if len(mediaTypes) == 0 {
+		_, _, err := codec.Negotiate(accept, produces...)
+		return nil, nil, err
+	}
+
+	var err error
+	for _, mediaType := range mediaTypes {
+		encoder := codec.GetEncoder(mediaType)
+		var buf bytes.Buffer
+		if e := encoder.Encode(&buf, value); e == nil {
+			return encoder, buf.Bytes(), nil
+		} else if err == nil {
+			err = fmt.Errorf("can not encode the result as [%v]: %w", mediaType, e)
+		}
+	}
+	return nil, nil, err
+}
+
+// applyPage 设置分页结果的总条数和各页链接
+func applyPage(page *common.Page, writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	if links := page.Links(request.URL); links != "" {
+		writer.Header().Set("Link", links)
+	}
+}
+
+// applyEntity 设置响应头和 cookie，并返回状态码
+func applyEntity(entity *common.ResponseEntity, writer http.ResponseWriter) int {
+	for key, values := range entity.Header {
+		for _, value := range values {
+			writer.Header().Add(key, value)
+		}
+	}
+	for _, cookie := range entity.Cookies {
+		http.SetCookie(writer, cookie)
+	}
+	if entity.Status == 0 {
+		return http.StatusOK
+	}
+	return entity.Status
+}
+
+// writeStream 流式响应，不缓存响应内容
+func writeStream(status int, contentType string, reader io.Reader, writer http.ResponseWriter) error {
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	util.SetResponseWriterHeader(writer, "Content-Type", contentType)
+	writer.WriteHeader(status)
+	if reader == nil {
+		return nil
+	}
+	_, err := io.Copy(writer, reader)
+	return err
+}
+
+// writeFile 文件下载
+//
+// 状态码为 200 或者 206 时支持 Range 和条件请求，其他状态码时响应整个文件
+func writeFile(status int, file *common.File, writer http.ResponseWriter, request *http.Request) error {
+	name := file.Name
+	if name == "" && file.Path != "" {
+		name = filepath.Base(file.Path)
+	}
+
+	disposition := "attachment"
+	if file.Inline {
+		disposition = "inline"
+	}
+	if name != "" {
+		disposition = mime.FormatMediaType(disposition, map[string]string{"filename": name})
+	}
+	util.SetResponseWriterHeader(writer, "Content-Disposition", disposition)
+
+	contentType := file.ContentType
+	if contentType == "" {
+		if contentType = mime.TypeByExtension(filepath.Ext(name)); contentType == "" {
+			contentType = mediaTypeOctetStream
+		}
+	}
+
+	if file.Path == "" {
+		return writeStream(status, contentType, file.Reader, writer)
+	}
+
+	f, err := os.Open(file.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return common.WrapHTTPError(http.StatusNotFound, err)
+		}
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	// ResponseEntity 设置了其他状态码时，直接以该状态码响应整个文件，Range 和条件请求只适用于 200
+	if status != http.StatusOK && status != http.StatusPartialContent {
+		util.SetResponseWriterHeader(writer, "Content-Length", strconv.FormatInt(info.Size(), 10))
+		return writeStream(status, contentType, f, writer)
+	}
+	// 支持 Range 和条件请求
+	util.SetResponseWriterHeader(writer, "Content-Type", contentType)
+	http.ServeContent(writer, request, name, info.ModTime(), f)
+	return nil
+}
+
+// isNil 指针、接口等是否为 nil
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return value.IsNil()
+	}
+	return false
 }
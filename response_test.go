@@ -0,0 +1,278 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 11:50 上午
+// version: 1.0.0
+// desc   : 响应类型和异常处理测试
+
+package gox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/core"
+	"github.com/yhyzgn/gox/util"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// errUnknown 未设置状态码的全局异常
+var errUnknown = &common.HTTPError{Detail: "unknown"}
+
+type userController struct {
+}
+
+func (e userController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/user/{id}").HandlerFunc(e.User).PathVariable("id").Min(1).Mapping()
+}
+
+func (userController) User(id int) (string, error) {
+	switch id {
+	case 1:
+		return "", fmt.Errorf("find user: %w", common.NotFound("The user does not exist.").WithCode("USER_NOT_FOUND").WithHeader("X-Reason", "missing"))
+	case 2:
+		return "", errors.New("database is down")
+	case 3:
+		return "", errUnknown
+	}
+	return "user", nil
+}
+
+func TestGoX_HTTPError(t *testing.T) {
+	server := NewGoX().Mapping("/api", userController{})
+
+	cases := []struct {
+		path    string
+		status  int
+		problem map[string]interface{}
+	}{
+		{"/api/user/1", http.StatusNotFound, map[string]interface{}{"type": "about:blank", "title": "Not Found", "status": float64(404), "detail": "The user does not exist.", "code": "USER_NOT_FOUND"}},
+		{"/api/user/2", http.StatusInternalServerError, map[string]interface{}{"type": "about:blank", "title": "Internal Server Error", "status": float64(500), "detail": "database is down"}},
+		{"/api/user/3", http.StatusInternalServerError, map[string]interface{}{"type": "about:blank", "title": "Internal Server Error", "status": float64(500), "detail": "unknown"}},
+		{"/api/user/0", http.StatusBadRequest, nil},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if recorder.Code != c.status {
+			t.Errorf("[%v] should response status %d, but %d", c.path, c.status, recorder.Code)
+		}
+		if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/problem+json") {
+			t.Errorf("[%v] should response problem details, but [%v]", c.path, contentType)
+		}
+
+		problem := make(map[string]interface{})
+		if err := json.Unmarshal(recorder.Body.Bytes(), &problem); err != nil {
+			t.Fatal(err)
+		}
+		if c.problem == nil {
+			// 校验错误在 errors 扩展字段中
+			if fields, ok := problem["errors"].([]interface{}); !ok || len(fields) != 1 {
+				t.Errorf("[%v] should response field errors, but %v", c.path, problem)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(problem, c.problem) {
+			t.Errorf("[%v] should response %v, but %v", c.path, c.problem, problem)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/user/1", nil))
+	if reason := recorder.Header().Get("X-Reason"); reason != "missing" {
+		t.Errorf("header of HTTPError should be responded, but [%v]", reason)
+	}
+	if errUnknown.Status != 0 {
+		t.Errorf("the shared HTTPError should not be modified, but status is %d", errUnknown.Status)
+	}
+}
+
+type QuotaError struct {
+	Limit int
+}
+
+func (qe *QuotaError) Error() string {
+	return fmt.Sprintf("quota %d exceeded", qe.Limit)
+}
+
+type errorController struct {
+}
+
+func (f errorController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/missing").HandlerFunc(f.Missing).Mapping()
+	mapper.Group("/quota", func(group *core.Mapper) {
+		group.HandleError(new(*QuotaError), func(writer http.ResponseWriter, request *http.Request, err error) {
+			var qe *QuotaError
+			errors.As(err, &qe)
+			util.ResponseJSONStatus(http.StatusTooManyRequests, writer, qe.Limit)
+		})
+		group.Get("/").HandlerFunc(f.Quota).Mapping()
+	})
+	mapper.Get("/quota/outside").HandlerFunc(f.Quota).Mapping()
+}
+
+func (errorController) Missing() (string, error) {
+	return "", common.NotFound("missing")
+}
+
+func (errorController) Quota() (string, error) {
+	return "", fmt.Errorf("request: %w", &QuotaError{Limit: 10})
+}
+
+func TestGoX_ErrorHandler(t *testing.T) {
+	server := NewGoX().
+		ErrorTypeHandler(new(*common.HTTPError), func(writer http.ResponseWriter, request *http.Request, err error) {
+			util.ResponseJSONStatus(http.StatusGone, writer, "advised")
+		}).
+		ErrorCodeHandler(http.StatusNotFound, func(writer http.ResponseWriter, request *http.Request) {
+			util.ResponseJSONStatus(http.StatusNotFound, writer, "custom 404")
+		}).
+		Mapping("/api", errorController{})
+
+	cases := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/api/missing", http.StatusGone, `"advised"`},
+		{"/api/quota", http.StatusTooManyRequests, "10"},
+		{"/api/quota/outside", http.StatusInternalServerError, ""},
+		{"/api/unknown", http.StatusNotFound, `"custom 404"`},
+	}
+	for _, c := range cases {
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if recorder.Code != c.status {
+			t.Errorf("[%v] should response status %d, but %d", c.path, c.status, recorder.Code)
+		}
+		if c.body != "" && recorder.Body.String() != c.body {
+			t.Errorf("[%v] should response %v, but %v", c.path, c.body, recorder.Body.String())
+		}
+	}
+}
+
+type responseController struct {
+}
+
+func (g responseController) Mapping(mapper *core.Mapper) {
+	mapper.Post("/entity").HandlerFunc(g.Entity).Mapping()
+	mapper.Get("/redirect").HandlerFunc(g.Redirect).Mapping()
+	mapper.Get("/stream").HandlerFunc(g.Stream).Mapping()
+	mapper.Get("/file").HandlerFunc(g.File).Mapping()
+	mapper.Post("/file").HandlerFunc(g.CreatedFile).Mapping()
+	mapper.Get("/bytes").HandlerFunc(g.Bytes).Mapping()
+	mapper.Get("/nil").HandlerFunc(g.Nil).Mapping()
+	mapper.Get("/text").HandlerFunc(g.Text).Mapping()
+	mapper.Get("/map").HandlerFunc(g.Map).Mapping()
+	mapper.Get("/xml").HandlerFunc(g.XML).Produces("application/xml").Mapping()
+}
+
+func (responseController) Entity() *common.ResponseEntity {
+	return common.Created("/api/book/1", &Book{Title: "gox", Price: 12}).WithCookie(&http.Cookie{Name: "sid", Value: "1"})
+}
+
+func (responseController) Redirect() *common.Redirect {
+	return common.NewRedirect("/api/text")
+}
+
+func (responseController) Stream() *common.Stream {
+	return common.NewStream(strings.NewReader("a,b"), "text/csv")
+}
+
+func (responseController) File() *common.File {
+	return common.NewFileReader(strings.NewReader("hello"), "hello.txt")
+}
+
+func (responseController) CreatedFile() *common.ResponseEntity {
+	return common.NewResponseEntity(http.StatusCreated, common.NewFile("LICENSE", ""))
+}
+
+func (responseController) Bytes() []byte {
+	return []byte{1, 2}
+}
+
+func (responseController) Nil() *Book {
+	return nil
+}
+
+func (responseController) Text() string {
+	return "text"
+}
+
+func (responseController) XML() Book {
+	return Book{Title: "gox", Price: 12}
+}
+
+func (responseController) Map() map[string]interface{} {
+	return map[string]interface{}{"name": "gox"}
+}
+
+func TestGoX_ResponseTypes(t *testing.T) {
+	server := NewGoX().Mapping("/api", responseController{})
+	license, err := ioutil.ReadFile("LICENSE")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		method string
+		path   string
+		accept string
+		status int
+		header map[string]string
+		body   string
+	}{
+		{http.MethodPost, "/api/entity", "", http.StatusCreated, map[string]string{"Location": "/api/book/1", "Set-Cookie": "sid=1"}, `{"title":"gox","price":12}`},
+		{http.MethodGet, "/api/redirect", "", http.StatusFound, map[string]string{"Location": "/api/text"}, ""},
+		{http.MethodGet, "/api/stream", "", http.StatusOK, map[string]string{"Content-Type": "text/csv"}, "a,b"},
+		{http.MethodGet, "/api/file", "", http.StatusOK, map[string]string{"Content-Disposition": "attachment; filename=hello.txt", "Content-Type": "text/plain; charset=utf-8"}, "hello"},
+		{http.MethodPost, "/api/file", "", http.StatusCreated, map[string]string{"Content-Disposition": "attachment; filename=LICENSE", "Content-Length": strconv.Itoa(len(license))}, string(license)},
+		{http.MethodGet, "/api/bytes", "", http.StatusOK, map[string]string{"Content-Type": "application/octet-stream"}, "\x01\x02"},
+		{http.MethodGet, "/api/nil", "", http.StatusNoContent, nil, ""},
+		{http.MethodGet, "/api/text", "", http.StatusOK, map[string]string{"Content-Type": "text/plain;charset=utf-8"}, "text"},
+		{http.MethodGet, "/api/text", "application/json", http.StatusOK, nil, `"text"`},
+		{http.MethodGet, "/api/map", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", http.StatusOK, map[string]string{"Content-Type": "application/json;charset=utf-8"}, `{"name":"gox"}`},
+		{http.MethodGet, "/api/map", "application/xml", http.StatusInternalServerError, nil, ""},
+		{http.MethodGet, "/api/xml", "application/xml", http.StatusOK, map[string]string{"Content-Type": "application/xml;charset=utf-8"}, "<Book><Title>gox</Title><Price>12</Price></Book>"},
+		{http.MethodGet, "/api/xml", "application/json", http.StatusNotAcceptable, nil, ""},
+	}
+	for _, c := range cases {
+		request := httptest.NewRequest(c.method, c.path, nil)
+		if c.accept != "" {
+			request.Header.Set("Accept", c.accept)
+		}
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, request)
+		if recorder.Code != c.status {
+			t.Errorf("[%v] should response status %d, but %d", c.path, c.status, recorder.Code)
+		}
+		for key, value := range c.header {
+			if actual := recorder.Header().Get(key); actual != value {
+				t.Errorf("[%v] should response header [%v: %v], but [%v]", c.path, key, value, actual)
+			}
+		}
+		if c.status != http.StatusFound && c.status < http.StatusBadRequest && recorder.Body.String() != c.body {
+			t.Errorf("[%v] should response %q, but %q", c.path, c.body, recorder.Body.String())
+		}
+	}
+}
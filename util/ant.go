@@ -0,0 +1,297 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 4:20 下午
+// version: 1.0.0
+// desc   : Ant 风格的路径匹配
+//			?      ->  一个字符，不包括 /
+//			*      ->  一段中的零个或多个字符
+//			**     ->  零段或多段
+//			{var}  ->  一段，并记录为变量，也可以是 {var:regexp}
+
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 段的类型
+const (
+	segmentLiteral    = iota // 普通字符
+	segmentVariable          // {var}
+	segmentStar              // *
+	segmentPattern           // 包含 ?、* 或 {var} 的段
+	segmentDoubleStar        // **
+)
+
+// segment 路径中的一段
+type segment struct {
+	kind    int
+	literal string         // 普通字符或者变量名
+	reg     *regexp.Regexp // 包含通配符的段
+	names   []string       // 段中的变量名
+}
+
+// AntPattern 预编译的 Ant 风格路径
+//
+// 匹配时忽略首尾的 /，/ 匹配所有请求（兼容旧的配置）
+// 多个路径都匹配同一请求时，用 Compare 比较其具体程度
+type AntPattern struct {
+	raw       string
+	segments  []segment
+	variables int // 变量个数
+	wildcards int // *、? 和变量的个数
+	doubles   int // ** 的个数
+	prefix    int // 第一个通配符之前的普通前缀长度
+}
+
+// CompileAntPattern 编译 Ant 风格路径
+func CompileAntPattern(pattern string) (*AntPattern, error) {
+	ap := &AntPattern{raw: pattern}
+	if pattern == "/" {
+		pattern = "/**"
+	}
+	ap.prefix = len(pattern)
+	if i := strings.IndexAny(pattern, "?*{"); i > -1 {
+		ap.prefix = i
+	}
+	for _, part := range splitPath(pattern) {
+		seg, err := ap.compileSegment(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path pattern [%v]: %v", ap.raw, err)
+		}
+		ap.segments = append(ap.segments, seg)
+	}
+	return ap, nil
+}
+
+// MustCompileAntPattern 编译 Ant 风格路径，失败时 panic
+func MustCompileAntPattern(pattern string) *AntPattern {
+	ap, err := CompileAntPattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return ap
+}
+
+// compileSegment 编译一段
+func (ap *AntPattern) compileSegment(part string) (segment, error) {
+	switch {
+	case part == "**":
+		ap.doubles++
+		return segment{kind: segmentDoubleStar}, nil
+	case part == "*":
+		ap.wildcards++
+		return segment{kind: segmentStar}, nil
+	case strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") && strings.Count(part, "{") == 1 && !strings.Contains(part, ":"):
+		ap.variables++
+		ap.wildcards++
+		return segment{kind: segmentVariable, literal: part[1 : len(part)-1]}, nil
+	case !strings.ContainsAny(part, "?*{"):
+		return segment{kind: segmentLiteral, literal: part}, nil
+	}
+
+	// 转为正则
+	var (
+		buf   strings.Builder
+		names []string
+	)
+	buf.WriteString("^")
+	for i := 0; i < len(part); i++ {
+		switch c := part[i]; c {
+		case '?':
+			ap.wildcards++
+			buf.WriteString("[^/]")
+		case '*':
+			ap.wildcards++
+			buf.WriteString("[^/]*")
+		case '{':
+			end := closingBrace(part[i:])
+			if end < 0 {
+				return segment{}, fmt.Errorf("unclosed variable in [%v]", part)
+			}
+			name, expr := part[i+1:i+end], "[^/]+"
+			if pos := strings.IndexByte(name, ':'); pos >= 0 {
+				name, expr = name[:pos], name[pos+1:]
+			}
+			if name == "" {
+				return segment{}, fmt.Errorf("empty variable name in [%v]", part)
+			}
+			ap.variables++
+			ap.wildcards++
+			names = append(names, name)
+			buf.WriteString("(" + expr + ")")
+			i += end
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteString("$")
+
+	reg, err := regexp.Compile(buf.String())
+	if err != nil {
+		return segment{}, err
+	}
+	if reg.NumSubexp() != len(names) {
+		return segment{}, fmt.Errorf("capturing groups are not supported in [%v]", part)
+	}
+	return segment{kind: segmentPattern, reg: reg, names: names}, nil
+}
+
+// String 原始路径
+func (ap *AntPattern) String() string {
+	return ap.raw
+}
+
+// Match 是否匹配请求路径
+func (ap *AntPattern) Match(path string) bool {
+	return ap.match(ap.segments, splitPath(path), nil)
+}
+
+// MatchVariables 匹配请求路径，并获取所有变量
+func (ap *AntPattern) MatchVariables(path string) (map[string]string, bool) {
+	variables := make(map[string]string, ap.variables)
+	if !ap.match(ap.segments, splitPath(path), variables) {
+		return nil, false
+	}
+	return variables, true
+}
+
+// match 逐段匹配，** 时回溯
+func (ap *AntPattern) match(segments []segment, parts []string, variables map[string]string) bool {
+	for len(segments) > 0 {
+		seg := segments[0]
+		if seg.kind == segmentDoubleStar {
+			// 连续的 ** 等同于一个
+			for len(segments) > 0 && segments[0].kind == segmentDoubleStar {
+				segments = segments[1:]
+			}
+			if len(segments) == 0 {
+				return true
+			}
+			for i := 0; i <= len(parts); i++ {
+				if ap.match(segments, parts[i:], variables) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 || !seg.matches(parts[0], variables) {
+			return false
+		}
+		segments, parts = segments[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// matches 是否匹配一段
+func (seg segment) matches(part string, variables map[string]string) bool {
+	switch seg.kind {
+	case segmentLiteral:
+		return seg.literal == part
+	case segmentStar:
+		return true
+	case segmentVariable:
+		if part == "" {
+			return false
+		}
+		if variables != nil {
+			variables[seg.literal] = part
+		}
+		return true
+	}
+	matches := seg.reg.FindStringSubmatch(part)
+	if matches == nil {
+		return false
+	}
+	if variables != nil {
+		for i, name := range seg.names {
+			variables[name] = matches[i+1]
+		}
+	}
+	return true
+}
+
+// Compare 比较两个路径的具体程度，越具体越优先
+//
+// 返回负数时 ap 更具体，正数时 other 更具体，0 时两者一样具体，依次比较：
+//
+//  1. ** 越少越具体，/ 等同于 /**
+//  2. *、? 和变量越少越具体
+//  3. 第一个通配符之前的普通前缀越长越具体
+func (ap *AntPattern) Compare(other *AntPattern) int {
+	if ap.doubles != other.doubles {
+		return ap.doubles - other.doubles
+	}
+	if ap.wildcards != other.wildcards {
+		return ap.wildcards - other.wildcards
+	}
+	return other.prefix - ap.prefix
+}
+
+// AntPatterns 一组 Ant 风格路径
+type AntPatterns []*AntPattern
+
+// Match 是否有任意一个匹配请求路径
+func (aps AntPatterns) Match(path string) bool {
+	for _, ap := range aps {
+		if ap.Match(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Best 获取匹配请求路径的最具体的路径，见 AntPattern.Compare
+//
+// 一样具体时取先添加的，都不匹配时返回 nil
+func (aps AntPatterns) Best(path string) *AntPattern {
+	var best *AntPattern
+	for _, ap := range aps {
+		if (best == nil || ap.Compare(best) < 0) && ap.Match(path) {
+			best = ap
+		}
+	}
+	return best
+}
+
+// closingBrace 获取与开头的 { 匹配的 } 的位置，变量的正则中可以包含 {}
+func closingBrace(part string) int {
+	depth := 0
+	for i := 0; i < len(part); i++ {
+		switch part[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitPath 按 / 分段，忽略首尾的 /
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 5:05 下午
+// version: 1.0.0
+// desc   : Ant 风格路径匹配测试
+
+package util
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestAntPattern_Match(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		matched bool
+	}{
+		{"/", "/api/user", true},
+		{"/api/user", "/api/user", true},
+		{"/api/user", "/api/user/", true},
+		{"/api/user", "/api/users", false},
+		{"/api/*", "/api/user", true},
+		{"/api/*", "/api/user/login", false},
+		{"/api/**", "/api", true},
+		{"/api/**", "/api/user/login", true},
+		{"/api/**/login", "/api/login", true},
+		{"/api/**/login", "/api/v1/user/login", true},
+		{"/api/**/login", "/api/v1/user/logout", false},
+		{"/api/*.do", "/api/login.do", true},
+		{"/api/*.do", "/api/loginXdo", false},
+		{"/api/*.do", "/api/user/login.do", false},
+		{"/api/user?", "/api/user1", true},
+		{"/api/user?", "/api/user12", false},
+		{"/api/{id}", "/api/12", true},
+		{"/api/{id}", "/api/12/x", false},
+		{"/api/{id:[0-9]{2}}", "/api/12", true},
+		{"/api/{id:[0-9]{2}}", "/api/123", false},
+		{"/**/*.js", "/static/js/app.js", true},
+	}
+	for _, c := range cases {
+		if matched := MustCompileAntPattern(c.pattern).Match(c.path); matched != c.matched {
+			t.Errorf("[%v] matching [%v] should be %v, but %v", c.pattern, c.path, c.matched, matched)
+		}
+	}
+
+	if _, err := CompileAntPattern("/api/{id"); err == nil {
+		t.Error("unclosed variable should fail")
+	}
+}
+
+func TestAntPattern_MatchVariables(t *testing.T) {
+	variables, ok := MustCompileAntPattern("/api/{group}/user-{id:[0-9]+}.json").MatchVariables("/api/admin/user-12.json")
+	if !ok || !reflect.DeepEqual(variables, map[string]string{"group": "admin", "id": "12"}) {
+		t.Errorf("should match variables, but %v, %v", variables, ok)
+	}
+}
+
+func TestAntPattern_Compare(t *testing.T) {
+	patterns := []*AntPattern{
+		MustCompileAntPattern("/"),
+		MustCompileAntPattern("/api/**/*.json"),
+		MustCompileAntPattern("/api/**"),
+		MustCompileAntPattern("/api/user/**"),
+		MustCompileAntPattern("/api/*"),
+		MustCompileAntPattern("/api/{id}/*"),
+		MustCompileAntPattern("/api/user/*"),
+		MustCompileAntPattern("/api/user"),
+	}
+	sort.SliceStable(patterns, func(i, j int) bool {
+		return patterns[i].Compare(patterns[j]) < 0
+	})
+	names := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		names = append(names, pattern.String())
+	}
+	// ** 越少越具体，其次 * 越少越具体，最后普通前缀越长越具体
+	expected := []string{"/api/user", "/api/user/*", "/api/*", "/api/{id}/*", "/api/user/**", "/api/**", "/", "/api/**/*.json"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("patterns should be sorted as %v, but %v", expected, names)
+	}
+
+	if cmp := MustCompileAntPattern("/").Compare(MustCompileAntPattern("/**")); cmp != 0 {
+		t.Errorf("[/] should be as specific as [/**], but %v", cmp)
+	}
+}
+
+func TestAntPatterns_Best(t *testing.T) {
+	patterns := AntPatterns{
+		MustCompileAntPattern("/**"),
+		MustCompileAntPattern("/api/**"),
+		MustCompileAntPattern("/api/*"),
+		MustCompileAntPattern("/api/login"),
+	}
+	cases := map[string]string{
+		"/api/login":      "/api/login",
+		"/api/logout":     "/api/*",
+		"/api/user/login": "/api/**",
+		"/static/app.js":  "/**",
+	}
+	for path, expected := range cases {
+		if best := patterns.Best(path); best == nil || best.String() != expected {
+			t.Errorf("the best pattern of [%v] should be [%v], but [%v]", path, expected, best)
+		}
+	}
+	if best := patterns[1:].Best("/static/app.js"); best != nil {
+		t.Errorf("no pattern should match [/static/app.js], but [%v]", best)
+	}
+}
+
+func BenchmarkAntPattern_Match(b *testing.B) {
+	pattern := MustCompileAntPattern("/api/**/user/{id}/*.json")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		pattern.Match("/api/v1/admin/user/12/profile.json")
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 5:40 下午
+// version: 1.0.0
+// desc   : 请求匹配条件
+//			用于过滤器和拦截器在 path 之外，按请求方法、Host、请求头或者自定义条件匹配
+
+package util
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/yhyzgn/gox/common"
+)
+
+// Condition 请求匹配条件
+//
+// 各项条件同时满足时才匹配，未配置的条件不限制，nil 匹配所有请求
+type Condition struct {
+	methods    []common.Method
+	hosts      []string
+	headers    map[string]string
+	predicates []func(request *http.Request) bool
+}
+
+// When 一个新的请求匹配条件
+func When() *Condition {
+	return new(Condition)
+}
+
+// Methods 请求方法是其中之一
+func (c *Condition) Methods(methods ...common.Method) *Condition {
+	c.methods = append(c.methods, methods...)
+	return c
+}
+
+// Hosts Host 是其中之一，忽略端口和大小写
+//
+// *.example.com 匹配 example.com 的所有子域名
+func (c *Condition) Hosts(hosts ...string) *Condition {
+	c.hosts = append(c.hosts, hosts...)
+	return c
+}
+
+// Header 请求头的值为 value，value 为空时只要求存在该请求头
+func (c *Condition) Header(name, value string) *Condition {
+	if c.headers == nil {
+		c.headers = make(map[string]string)
+	}
+	c.headers[name] = value
+	return c
+}
+
+// Predicate 自定义条件
+func (c *Condition) Predicate(predicate func(request *http.Request) bool) *Condition {
+	c.predicates = append(c.predicates, predicate)
+	return c
+}
+
+// Matches 请求是否满足所有条件
+func (c *Condition) Matches(request *http.Request) bool {
+	if c == nil {
+		return true
+	}
+	if len(c.methods) > 0 && !c.matchMethod(request.Method) {
+		return false
+	}
+	if len(c.hosts) > 0 && !c.matchHost(request.Host) {
+		return false
+	}
+	for name, value := range c.headers {
+		values, ok := request.Header[http.CanonicalHeaderKey(name)]
+		if !ok || value != "" && !contains(values, value) {
+			return false
+		}
+	}
+	for _, predicate := range c.predicates {
+		if !predicate(request) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchMethod 请求方法是否匹配
+func (c *Condition) matchMethod(method string) bool {
+	for _, m := range c.methods {
+		if strings.EqualFold(string(m), method) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchHost Host 是否匹配
+func (c *Condition) matchHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	for _, pattern := range c.hosts {
+		pattern = strings.ToLower(pattern)
+		if pattern == host || strings.HasPrefix(pattern, "*.") && strings.HasSuffix(host, pattern[1:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains 是否包含该值
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// StripContextPath 去除请求路径开头的 ContextPath
+//
+// 只作为前缀去除，/gox/api 去除 /gox 后为 /api，/api/gox 不变
+func StripContextPath(path, contextPath string) string {
+	contextPath = strings.TrimSuffix(contextPath, "/")
+	if contextPath == "" {
+		return path
+	}
+	if !strings.HasPrefix(contextPath, "/") {
+		contextPath = "/" + contextPath
+	}
+	if path == contextPath {
+		return "/"
+	}
+	if strings.HasPrefix(path, contextPath+"/") {
+		return path[len(contextPath):]
+	}
+	return path
+}
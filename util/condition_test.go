@@ -0,0 +1,84 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 6:05 下午
+// version: 1.0.0
+// desc   : 请求匹配条件测试
+
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCondition_Matches(t *testing.T) {
+	condition := When().
+		Methods(http.MethodPost, http.MethodDelete).
+		Hosts("*.gox.io", "localhost").
+		Header("X-Token", "").
+		Predicate(func(request *http.Request) bool {
+			return request.URL.Query().Get("dry") == ""
+		})
+
+	cases := []struct {
+		method  string
+		url     string
+		token   bool
+		matched bool
+	}{
+		{http.MethodPost, "http://api.gox.io/admin", true, true},
+		{http.MethodDelete, "http://localhost:8080/admin", true, true},
+		{http.MethodGet, "http://api.gox.io/admin", true, false},
+		{http.MethodPost, "http://gox.com/admin", true, false},
+		{http.MethodPost, "http://api.gox.io/admin", false, false},
+		{http.MethodPost, "http://api.gox.io/admin?dry=1", true, false},
+	}
+	for _, c := range cases {
+		request := httptest.NewRequest(c.method, c.url, nil)
+		if c.token {
+			request.Header.Set("X-Token", "gox")
+		}
+		if matched := condition.Matches(request); matched != c.matched {
+			t.Errorf("[%v %v] matching should be %v, but %v", c.method, c.url, c.matched, matched)
+		}
+	}
+
+	var none *Condition
+	if !none.Matches(httptest.NewRequest(http.MethodGet, "/", nil)) {
+		t.Error("nil condition should match all requests")
+	}
+}
+
+func TestStripContextPath(t *testing.T) {
+	cases := []struct {
+		path        string
+		contextPath string
+		expected    string
+	}{
+		{"/gox/api", "/gox", "/api"},
+		{"/gox", "/gox/", "/"},
+		{"/api/gox/user", "/gox", "/api/gox/user"},
+		{"/goxx/api", "/gox", "/goxx/api"},
+		{"/api", "", "/api"},
+	}
+	for _, c := range cases {
+		if actual := StripContextPath(c.path, c.contextPath); actual != c.expected {
+			t.Errorf("[%v] stripped [%v] should be [%v], but [%v]", c.path, c.contextPath, c.expected, actual)
+		}
+	}
+}
@@ -21,10 +21,48 @@
 package util
 
 import (
+	"encoding"
+	"fmt"
+	"reflect"
 	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	typeTime            = reflect.TypeOf(time.Time{})
+	typeDuration        = reflect.TypeOf(time.Duration(0))
+	typeTextUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	// 时间格式，按顺序尝试
+	timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02"}
+
+	// 自定义类型的转换器
+	convertersMu sync.RWMutex
+	converters   = make(map[reflect.Type]Converter)
+
+	// 基础类型
+	kindTypes = map[reflect.Kind]reflect.Type{
+		reflect.String:  reflect.TypeOf(""),
+		reflect.Bool:    reflect.TypeOf(false),
+		reflect.Int:     reflect.TypeOf(0),
+		reflect.Int8:    reflect.TypeOf(int8(0)),
+		reflect.Int16:   reflect.TypeOf(int16(0)),
+		reflect.Int32:   reflect.TypeOf(int32(0)),
+		reflect.Int64:   reflect.TypeOf(int64(0)),
+		reflect.Uint:    reflect.TypeOf(uint(0)),
+		reflect.Uint8:   reflect.TypeOf(uint8(0)),
+		reflect.Uint16:  reflect.TypeOf(uint16(0)),
+		reflect.Uint32:  reflect.TypeOf(uint32(0)),
+		reflect.Uint64:  reflect.TypeOf(uint64(0)),
+		reflect.Float32: reflect.TypeOf(float32(0)),
+		reflect.Float64: reflect.TypeOf(float64(0)),
+	}
 )
 
 // StringToInt String 转为不同长度 int
+//
+// 转换失败时为 0，需要错误信息时使用 ConvertValue
 func StringToInt(value string, size int) int64 {
 	it, err := strconv.ParseInt(value, 10, size)
 	if err == nil {
@@ -34,6 +72,8 @@ func StringToInt(value string, size int) int64 {
 }
 
 // StringToUInt String 转为不同长度的 uint
+//
+// 转换失败时为 0，需要错误信息时使用 ConvertValue
 func StringToUInt(value string, size int) uint64 {
 	it, err := strconv.ParseUint(value, 10, size)
 	if err == nil {
@@ -43,6 +83,8 @@ func StringToUInt(value string, size int) uint64 {
 }
 
 // StringToFloat String 转为不同长度的 float
+//
+// 转换失败时为 0，需要错误信息时使用 ConvertValue
 func StringToFloat(value string, size int) float64 {
 	ft, err := strconv.ParseFloat(value, size)
 	if err == nil {
@@ -50,3 +92,138 @@ func StringToFloat(value string, size int) float64 {
 	}
 	return 0
 }
+
+// Converter 字符串转换器，返回值需要是注册的类型或者可以转换为该类型
+type Converter func(value string) (interface{}, error)
+
+// ConversionError 参数转换错误
+type ConversionError struct {
+	Name  string       // 参数名，可为空
+	Type  reflect.Type // 期望的类型
+	Value string       // 接收到的值
+	Err   error        // 原始错误
+}
+
+// Error 错误信息
+func (ce *ConversionError) Error() string {
+	if ce.Name == "" {
+		return fmt.Sprintf("Can not convert [%v] to type [%v]: %v", ce.Value, ce.Type, ce.Err)
+	}
+	return fmt.Sprintf("The param [%v] expects type [%v], but received [%v]: %v", ce.Name, ce.Type, ce.Value, ce.Err)
+}
+
+// Unwrap 原始错误
+func (ce *ConversionError) Unwrap() error {
+	return ce.Err
+}
+
+// RegisterConverter 注册自定义类型的转换器
+//
+// 已注册的类型优先使用转换器，同一类型重复注册时覆盖
+func RegisterConverter(tp reflect.Type, converter Converter) {
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters[tp] = converter
+}
+
+// lookupConverter 查找已注册的转换器
+func lookupConverter(tp reflect.Type) Converter {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	return converters[tp]
+}
+
+// ConvertValue 将字符串转换为指定类型的值
+//
+// 支持已注册转换器的类型、基础类型及其自定义类型、指针、time.Time、time.Duration 和 encoding.TextUnmarshaler
+// 转换失败时返回 *ConversionError
+func ConvertValue(tp reflect.Type, value string) (reflect.Value, error) {
+	val, err := convertValue(tp, value)
+	if err != nil {
+		if _, ok := err.(*ConversionError); !ok {
+			err = &ConversionError{Type: tp, Value: value, Err: err}
+		}
+		return reflect.Value{}, err
+	}
+	return val, nil
+}
+
+// convertValue 具体的转换操作
+func convertValue(tp reflect.Type, value string) (reflect.Value, error) {
+	if converter := lookupConverter(tp); converter != nil {
+		res, err := converter(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		val := reflect.ValueOf(res)
+		if !val.IsValid() {
+			return reflect.Zero(tp), nil
+		}
+		if val.Type() != tp {
+			if !val.Type().ConvertibleTo(tp) {
+				return reflect.Value{}, fmt.Errorf("the converter returned [%v]", val.Type())
+			}
+			val = val.Convert(tp)
+		}
+		return val, nil
+	}
+
+	switch {
+	case tp.Kind() == reflect.Ptr:
+		// 指针，转换后取地址
+		elem, err := ConvertValue(tp.Elem(), value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		ptr := reflect.New(tp.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, nil
+	case tp == typeTime:
+		for _, layout := range timeLayouts {
+			if tm, err := time.Parse(layout, value); err == nil {
+				return reflect.ValueOf(tm), nil
+			}
+		}
+		return reflect.Value{}, fmt.Errorf("supported layouts are %v", timeLayouts)
+	case tp == typeDuration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(d), nil
+	case reflect.PtrTo(tp).Implements(typeTextUnmarshaler):
+		ptr := reflect.New(tp)
+		if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return reflect.Value{}, err
+		}
+		return ptr.Elem(), nil
+	}
+
+	var (
+		arg interface{}
+		err error
+	)
+	switch tp.Kind() {
+	case reflect.String:
+		arg = value
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		arg, err = strconv.ParseInt(value, 10, tp.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		arg, err = strconv.ParseUint(value, 10, tp.Bits())
+	case reflect.Float32, reflect.Float64:
+		arg, err = strconv.ParseFloat(value, tp.Bits())
+	case reflect.Bool:
+		arg, err = strconv.ParseBool(value)
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported type [%v]", tp)
+	}
+	if err != nil {
+		// 只保留原因，如 invalid syntax、value out of range
+		if ne, ok := err.(*strconv.NumError); ok {
+			err = ne.Err
+		}
+		return reflect.Value{}, err
+	}
+	// 转换为具体类型，兼容自定义类型，如 type Status int
+	return reflect.ValueOf(arg).Convert(tp), nil
+}
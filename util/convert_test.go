@@ -0,0 +1,72 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 6:20 下午
+// version: 1.0.0
+// desc   : 类型转换测试
+
+package util
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Level int
+
+func TestConvertValue(t *testing.T) {
+	val, err := ConvertValue(reflect.TypeOf(new(int)), "12")
+	if err != nil || *(val.Interface().(*int)) != 12 {
+		t.Errorf("should be converted to *int, but %v, %v", val, err)
+	}
+
+	_, err = ConvertValue(reflect.TypeOf(0), "abc")
+	ce, ok := err.(*ConversionError)
+	if !ok || ce.Type != reflect.TypeOf(0) || ce.Value != "abc" {
+		t.Fatalf("should be conversion error, but %v", err)
+	}
+	ce.Name = "age"
+	if !strings.Contains(ce.Error(), "[age] expects type [int], but received [abc]") {
+		t.Errorf("unexpected message [%v]", ce.Error())
+	}
+
+	if _, err = ConvertValue(reflect.TypeOf(struct{}{}), "x"); err == nil {
+		t.Error("unsupported type should be reported")
+	}
+	if val = StringToValue(reflect.Int, "abc"); val.Int() != 0 {
+		t.Errorf("StringToValue should fall back to zero, but %v", val)
+	}
+}
+
+func TestRegisterConverter(t *testing.T) {
+	levels := map[string]int{"low": 1, "high": 2}
+	RegisterConverter(reflect.TypeOf(Level(0)), func(value string) (interface{}, error) {
+		if level, ok := levels[value]; ok {
+			return level, nil
+		}
+		return nil, errors.New("unknown level")
+	})
+
+	val, err := ConvertValue(reflect.TypeOf(Level(0)), "high")
+	if err != nil || val.Interface().(Level) != 2 {
+		t.Errorf("should be converted by converter, but %v, %v", val, err)
+	}
+	if _, err = ConvertValue(reflect.TypeOf(Level(0)), "middle"); !errors.As(err, new(*ConversionError)) {
+		t.Errorf("converter error should be wrapped, but %v", err)
+	}
+}
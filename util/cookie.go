@@ -0,0 +1,128 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 1:40 下午
+// version: 1.0.0
+// desc   : cookie 签名和加密工具
+
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrCookieKeyMissing 未配置 cookie 密钥
+	ErrCookieKeyMissing = errors.New("cookie key is missing")
+
+	// ErrInvalidCookie cookie 签名不匹配或者无法解密
+	ErrInvalidCookie = errors.New("invalid cookie value")
+)
+
+// SignCookieValue 使用 HMAC-SHA256 签名 cookie 值
+//
+// 签名包含 cookie 名称，格式为 base64(value).base64(mac)
+func SignCookieValue(key []byte, name, value string) (string, error) {
+	if len(key) == 0 {
+		return "", ErrCookieKeyMissing
+	}
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(value))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(cookieMAC(key, name, encoded)), nil
+}
+
+// VerifyCookieValue 校验签名并返回原始的 cookie 值
+func VerifyCookieValue(key []byte, name, signed string) (string, error) {
+	if len(key) == 0 {
+		return "", ErrCookieKeyMissing
+	}
+	pos := strings.LastIndexByte(signed, '.')
+	if pos < 0 {
+		return "", ErrInvalidCookie
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signed[pos+1:])
+	if err != nil || !hmac.Equal(mac, cookieMAC(key, name, signed[:pos])) {
+		return "", ErrInvalidCookie
+	}
+	value, err := base64.RawURLEncoding.DecodeString(signed[:pos])
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	return string(value), nil
+}
+
+// EncryptCookieValue 使用 AES-GCM 加密 cookie 值
+//
+// 密钥经 SHA-256 转为 32 字节，cookie 名称作为附加数据，格式为 base64(nonce+ciphertext)
+func EncryptCookieValue(key []byte, name, value string) (string, error) {
+	aead, err := cookieAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptCookieValue 解密 cookie 值
+func DecryptCookieValue(key []byte, name, encrypted string) (string, error) {
+	aead, err := cookieAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(encrypted)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrInvalidCookie
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	value, err := aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	return string(value), nil
+}
+
+// cookieMAC 计算 cookie 名称和值的 HMAC
+func cookieMAC(key []byte, name, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{'|'})
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+// cookieAEAD 创建 AES-GCM 加密器
+func cookieAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, ErrCookieKeyMissing
+	}
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
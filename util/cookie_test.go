@@ -0,0 +1,65 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 2:10 下午
+// version: 1.0.0
+// desc   : cookie 签名和加密测试
+
+package util
+
+import (
+	"testing"
+)
+
+func TestSignCookieValue(t *testing.T) {
+	key := []byte("secret")
+	signed, err := SignCookieValue(key, "uid", "gox.12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := VerifyCookieValue(key, "uid", signed); err != nil || value != "gox.12" {
+		t.Errorf("should be verified as [gox.12], but [%v], %v", value, err)
+	}
+	if _, err := VerifyCookieValue(key, "sid", signed); err != ErrInvalidCookie {
+		t.Errorf("signed value of another cookie should be invalid, but %v", err)
+	}
+	if _, err := VerifyCookieValue([]byte("other"), "uid", signed); err != ErrInvalidCookie {
+		t.Errorf("signed value of another key should be invalid, but %v", err)
+	}
+	if _, err := SignCookieValue(nil, "uid", "gox"); err != ErrCookieKeyMissing {
+		t.Errorf("sign without key should fail, but %v", err)
+	}
+}
+
+func TestEncryptCookieValue(t *testing.T) {
+	key := []byte("secret")
+	encrypted, err := EncryptCookieValue(key, "uid", "gox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encrypted == "gox" {
+		t.Error("value should be encrypted")
+	}
+	if value, err := DecryptCookieValue(key, "uid", encrypted); err != nil || value != "gox" {
+		t.Errorf("should be decrypted as [gox], but [%v], %v", value, err)
+	}
+	if _, err := DecryptCookieValue(key, "sid", encrypted); err != ErrInvalidCookie {
+		t.Errorf("encrypted value of another cookie should be invalid, but %v", err)
+	}
+	if _, err := DecryptCookieValue(key, "uid", "abc"); err != ErrInvalidCookie {
+		t.Errorf("broken value should be invalid, but %v", err)
+	}
+}
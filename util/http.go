@@ -24,7 +24,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
@@ -34,17 +36,62 @@ import (
 	"github.com/yhyzgn/gox/common"
 )
 
+// ErrBodyTooLarge 请求体超过了限制的大小
+var ErrBodyTooLarge = errors.New("request body too large")
+
+// bodyLimiter 限制请求体大小
+type bodyLimiter struct {
+	io.ReadCloser
+	remaining int64
+}
+
+// Read 读取请求体，超过限制时返回 ErrBodyTooLarge
+func (bl *bodyLimiter) Read(p []byte) (int, error) {
+	if bl.remaining <= 0 {
+		// 多读一个字节，判断是否刚好读完
+		var one [1]byte
+		if n, _ := bl.ReadCloser.Read(one[:]); n > 0 {
+			return 0, ErrBodyTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > bl.remaining {
+		p = p[:bl.remaining]
+	}
+	n, err := bl.ReadCloser.Read(p)
+	bl.remaining -= int64(n)
+	return n, err
+}
+
+// LimitRequestBody 限制请求体大小
+//
+// 读取超过 limit 字节时返回 ErrBodyTooLarge，limit 不大于 0 时不限制
+func LimitRequestBody(req *http.Request, limit int64) {
+	if req == nil || req.Body == nil || limit <= 0 {
+		return
+	}
+	req.Body = &bodyLimiter{ReadCloser: req.Body, remaining: limit}
+}
+
+// ReadRequestBody 读取并复用 request.Body
+//
+// 读取失败时（如超过限制的大小）返回错误
+func ReadRequestBody(req *http.Request) ([]byte, error) {
+	if req == nil || req.Body == nil {
+		return nil, nil
+	}
+	bs, err := ioutil.ReadAll(req.Body)
+	req.Body = ioutil.NopCloser(bytes.NewBuffer(bs))
+	return bs, err
+}
+
 // RecycleRequestBody 复用 request.Body
 //
 // 获取到本来的 request.Body
 // 再把获取到的设置回去
 func RecycleRequestBody(req *http.Request) []byte {
-	if req != nil && req.Body != nil {
-		bs, _ := ioutil.ReadAll(req.Body)
-		req.Body = ioutil.NopCloser(bytes.NewBuffer(bs))
-		return bs
-	}
-	return nil
+	bs, _ := ReadRequestBody(req)
+	return bs
 }
 
 // SetRequestAttribute 给 request 添加属性
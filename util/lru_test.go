@@ -0,0 +1,53 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 11:20 下午
+// version: 1.0.0
+// desc   : LRU 缓存测试
+
+package util
+
+import (
+	"testing"
+)
+
+func TestLRU(t *testing.T) {
+	lru := NewLRU(2)
+	lru.Add("a", 1)
+	lru.Add("b", 2)
+	if value, ok := lru.Get("a"); !ok || value != 1 {
+		t.Errorf("[a] should be cached, but %v", value)
+	}
+
+	// b 最久未使用，被淘汰
+	lru.Add("c", 3)
+	if _, ok := lru.Get("b"); ok {
+		t.Error("[b] should be evicted")
+	}
+	if lru.Len() != 2 {
+		t.Errorf("should cache 2 items, but %d", lru.Len())
+	}
+
+	lru.Add("a", 4)
+	if value, _ := lru.Get("a"); value != 4 {
+		t.Errorf("[a] should be updated to 4, but %v", value)
+	}
+
+	lru.Purge()
+	if _, ok := lru.Get("a"); ok || lru.Len() != 0 {
+		t.Error("cache should be purged")
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 )
 
 const (
@@ -35,6 +36,7 @@ const (
 
 var (
 	regRESTful, _ = regexp.Compile(PatternRESTful)
+	antPatterns   sync.Map // 已编译的 Ant 风格路径
 )
 
 // IsRESTful path 是否是 RESTful 格式
@@ -75,27 +77,33 @@ func GetPathVariableIndex(name string, path string) int {
 	return -1
 }
 
+// MatchedRequestByPathPattern 请求路径是否匹配 Ant 风格路径
+//
+// 编译后的路径会被缓存，见 CompileAntPattern
 func MatchedRequestByPathPattern(requestPath string, pattern string) bool {
-	//pattern = strings.ReplaceAll(pattern, ".", "\\.")
-	// 先匹配
-	if reg, e := regexp.Compile("^.*?\\*+?.*$"); e == nil && reg.MatchString(pattern) {
-		// 再替换
-		if reg, e = regexp.Compile("\\*+?"); e == nil && reg.MatchString(pattern) {
-			temp := reg.ReplaceAllString(pattern, ".+?")
-
-			// 匹配 pattern 和 path
-			matched, err := regexp.MatchString("^"+temp+"$", requestPath)
-			return matched && err == nil
-		}
-	}
-	return false
+	ap, err := cachedAntPattern(pattern)
+	return err == nil && ap.Match(requestPath)
 }
 
+// IsExcludedRequest 请求路径是否匹配任意一个排除路径
 func IsExcludedRequest(requestPath string, excludes map[string]bool) (excluded bool) {
-	for exclude, _ := range excludes {
+	for exclude := range excludes {
 		if exclude == requestPath || MatchedRequestByPathPattern(requestPath, exclude) {
-			excluded = true
+			return true
 		}
 	}
 	return
 }
+
+// cachedAntPattern 获取缓存的 Ant 风格路径
+func cachedAntPattern(pattern string) (*AntPattern, error) {
+	if ap, ok := antPatterns.Load(pattern); ok {
+		return ap.(*AntPattern), nil
+	}
+	ap, err := CompileAntPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	antPatterns.Store(pattern, ap)
+	return ap, nil
+}
@@ -23,7 +23,6 @@ package util
 import (
 	"bytes"
 	"reflect"
-	"strconv"
 	"strings"
 )
 
@@ -68,61 +67,16 @@ func FillSuffix(src, ch string, ln int) string {
 }
 
 // StringToValue 将字符串转换为其他类型
+//
+// 转换失败时为对应类型的零值，不支持的类型返回无效值，需要错误信息时使用 ConvertValue
 func StringToValue(kind reflect.Kind, value string) reflect.Value {
-	var arg reflect.Value
-	switch kind {
-	case reflect.String:
-		arg = reflect.ValueOf(value)
-		break
-	case reflect.Int:
-		it, err := strconv.Atoi(value)
-		if err == nil {
-			arg = reflect.ValueOf(it)
-		} else {
-			arg = reflect.ValueOf(0)
-		}
-		break
-	case reflect.Int8:
-		arg = reflect.ValueOf(int8(StringToInt(value, 8)))
-		break
-	case reflect.Int16:
-		arg = reflect.ValueOf(int16(StringToInt(value, 16)))
-		break
-	case reflect.Int32:
-		arg = reflect.ValueOf(int32(StringToInt(value, 32)))
-		break
-	case reflect.Int64:
-		arg = reflect.ValueOf(StringToInt(value, 64))
-		break
-	case reflect.Uint:
-		arg = reflect.ValueOf(uint(StringToUInt(value, 0)))
-		break
-	case reflect.Uint8:
-		arg = reflect.ValueOf(uint8(StringToUInt(value, 8)))
-		break
-	case reflect.Uint16:
-		arg = reflect.ValueOf(uint16(StringToUInt(value, 16)))
-		break
-	case reflect.Uint32:
-		arg = reflect.ValueOf(uint32(StringToUInt(value, 32)))
-		break
-	case reflect.Uint64:
-		arg = reflect.ValueOf(StringToUInt(value, 64))
-		break
-	case reflect.Float32:
-		arg = reflect.ValueOf(StringToFloat(value, 32))
-		break
-	case reflect.Float64:
-		arg = reflect.ValueOf(StringToFloat(value, 64))
-		break
-	case reflect.Bool:
-		bl, err := strconv.ParseBool(value)
-		if err == nil {
-			arg = reflect.ValueOf(bl)
-		} else {
-			arg = reflect.ValueOf(false)
-		}
-		break
+	tp, ok := kindTypes[kind]
+	if !ok {
+		return reflect.Value{}
+	}
+	arg, err := ConvertValue(tp, value)
+	if err != nil {
+		return reflect.Zero(tp)
 	}
 	return arg
 }
@@ -0,0 +1,420 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 10:12 上午
+// version: 1.0.0
+// desc   : 路由前缀树
+//			按 path 分段建树，静态段 > 参数段 > 通配段 依次匹配
+//			参数段支持类型约束 {id:int}、正则约束 {id:[0-9]+}、可选 {id?} 以及末尾的通配 {path...}
+//			同一位置上约束不同的参数段，剩余 path 和请求方法都相同时无法区分，注册时报错
+
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	wildcardSegment = "*"   // 匿名通配段，匹配剩余的全部路径
+	catchAllSuffix  = "..." // 具名通配段后缀，如 {path...}
+	optionalSuffix  = "?"   // 可选参数段后缀，如 {id?}
+)
+
+var (
+	// 内置的参数类型约束
+	typedConstraints = map[string]func(string) bool{
+		"int": func(value string) bool {
+			_, err := strconv.ParseInt(value, 10, 64)
+			return err == nil
+		},
+		"uint": func(value string) bool {
+			_, err := strconv.ParseUint(value, 10, 64)
+			return err == nil
+		},
+		"float": func(value string) bool {
+			_, err := strconv.ParseFloat(value, 64)
+			return err == nil
+		},
+		"bool": func(value string) bool {
+			_, err := strconv.ParseBool(value)
+			return err == nil
+		},
+		"uuid": regexp.MustCompile("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$").MatchString,
+	}
+)
+
+type nodeKind uint8
+
+const (
+	kindStatic   nodeKind = iota // 静态段，如 /user
+	kindParam                    // 参数段，如 /{id}
+	kindWildcard                 // 通配段，如 /* 或 /{path...}
+)
+
+// constraint 参数段约束
+type constraint struct {
+	expr  string            // 约束表达式，类型名或正则
+	match func(string) bool // 校验参数值
+}
+
+// segment 解析后的 path 段
+type segment struct {
+	kind       nodeKind    // 段类型
+	value      string      // 静态段的值
+	name       string      // 参数名
+	optional   bool        // 是否可选
+	constraint *constraint // 参数约束
+}
+
+// node 路由树节点
+type node struct {
+	kind       nodeKind         // 节点类型
+	constraint *constraint      // 参数节点的约束
+	statics    map[string]*node // 静态子节点，按 path 段索引
+	params     []*node          // 参数子节点，有约束的排在前面
+	wildcard   *node            // 通配子节点
+	wires      []*HandlerWire   // 挂载在当前节点上的处理器
+}
+
+// newNode 创建新节点
+func newNode(kind nodeKind) *node {
+	return &node{kind: kind}
+}
+
+// insert 按 path 段向树中插入处理器
+//
+// 返回 path 中按顺序出现的参数名
+func (n *node) insert(path string, wire *HandlerWire) ([]string, error) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// 末尾连续的可选段，每一个前缀都是一条路由
+	firstOptional := len(segments)
+	for firstOptional > 0 && segments[firstOptional-1].optional {
+		firstOptional--
+	}
+
+	current := n
+	parents := make([]*node, len(segments))
+	terminals := make([]*node, 0)
+	variables := make([]string, 0)
+	if firstOptional == 0 {
+		terminals = append(terminals, current)
+	}
+	for i, seg := range segments {
+		parents[i] = current
+		current = current.child(seg)
+		if seg.kind != kindStatic {
+			variables = append(variables, seg.name)
+		}
+		if i+1 >= firstOptional {
+			terminals = append(terminals, current)
+		}
+	}
+
+	// 先检查冲突，再挂载处理器
+	for _, terminal := range terminals {
+		for _, exist := range terminal.wires {
+			if md := exist.overlaps(wire); md != "" {
+				return nil, fmt.Errorf("the route [%v] conflicts with [%v] on http method [%v]", path, exist.Path, md)
+			}
+		}
+	}
+
+	// 同一位置上约束不同的参数段，剩余 path 相同时无法确定由谁处理
+	for i, seg := range segments {
+		if seg.kind != kindParam || seg.constraint == nil {
+			continue
+		}
+		for _, sibling := range parents[i].params {
+			if sibling.constraint == nil || sibling.constraint.expression() == seg.constraint.expression() {
+				continue
+			}
+			for j := i; j < len(segments); j++ {
+				if j+1 < firstOptional {
+					continue
+				}
+				terminal := sibling.find(segments[i+1 : j+1])
+				if terminal == nil {
+					continue
+				}
+				for _, exist := range terminal.wires {
+					if md := exist.overlaps(wire); md != "" {
+						return nil, fmt.Errorf("the route [%v] is ambiguous with [%v] on http method [%v], constrained params at the same position can not be told apart", path, exist.Path, md)
+					}
+				}
+			}
+		}
+	}
+	for _, terminal := range terminals {
+		terminal.wires = append(terminal.wires, wire)
+	}
+	return variables, nil
+}
+
+// child 获取或创建 path 段对应的子节点
+func (n *node) child(seg *segment) *node {
+	switch seg.kind {
+	case kindParam:
+		for _, param := range n.params {
+			if param.constraint.expression() == seg.constraint.expression() {
+				return param
+			}
+		}
+		child := newNode(kindParam)
+		child.constraint = seg.constraint
+		if seg.constraint == nil {
+			// 无约束的放最后
+			n.params = append(n.params, child)
+		} else {
+			// 有约束的插入到无约束节点之前
+			index := len(n.params)
+			for index > 0 && n.params[index-1].constraint == nil {
+				index--
+			}
+			n.params = append(n.params, nil)
+			copy(n.params[index+1:], n.params[index:])
+			n.params[index] = child
+		}
+		return child
+	case kindWildcard:
+		if n.wildcard == nil {
+			n.wildcard = newNode(kindWildcard)
+		}
+		return n.wildcard
+	default:
+		if n.statics == nil {
+			n.statics = make(map[string]*node)
+		}
+		child, ok := n.statics[seg.value]
+		if !ok {
+			child = newNode(kindStatic)
+			n.statics[seg.value] = child
+		}
+		return child
+	}
+}
+
+// find 按 path 段查找已有的子节点，不存在时为 nil
+func (n *node) find(segments []*segment) *node {
+	current := n
+	for _, seg := range segments {
+		switch seg.kind {
+		case kindParam:
+			var next *node
+			for _, param := range current.params {
+				if param.constraint.expression() == seg.constraint.expression() {
+					next = param
+					break
+				}
+			}
+			current = next
+		case kindWildcard:
+			current = current.wildcard
+		default:
+			current = current.statics[seg.value]
+		}
+		if current == nil {
+			return nil
+		}
+	}
+	return current
+}
+
+// has 该节点上是否注册了该处理器
+func (n *node) has(wire *HandlerWire) bool {
+	for _, hw := range n.wires {
+		if hw == wire {
+			return true
+		}
+	}
+	return false
+}
+
+// match 一个匹配到的节点及其 path 参数值
+type match struct {
+	node   *node
+	values []string
+}
+
+// lookup 查找 path 能匹配到的所有节点
+//
+// path 为去除首尾 / 后的剩余路径，values 用于收集参数值；
+// 匹配结果按 静态段 > 参数段（有约束的优先） > 通配段 的优先级追加到 matches 中，
+// 以便优先级高的节点不支持请求方法时，还能回退到下一个节点
+func (n *node) lookup(path string, values []string, matches []match) []match {
+	if path == "" {
+		if len(n.wires) > 0 {
+			matches = append(matches, match{node: n, values: append([]string(nil), values...)})
+		}
+		return matches
+	}
+
+	// 取出当前段和剩余 path
+	seg, rest := path, ""
+	if i := strings.IndexByte(path, '/'); i > -1 {
+		seg, rest = path[:i], path[i+1:]
+	}
+
+	// 优先匹配静态段
+	if child, ok := n.statics[seg]; ok {
+		matches = child.lookup(rest, values, matches)
+	}
+
+	// 再匹配参数段，有约束的优先
+	if seg != "" {
+		for _, param := range n.params {
+			if param.constraint != nil && !param.constraint.match(seg) {
+				continue
+			}
+			matches = param.lookup(rest, append(values, seg), matches)
+		}
+	}
+
+	// 最后匹配通配段
+	if n.wildcard != nil && len(n.wildcard.wires) > 0 {
+		vs := make([]string, len(values), len(values)+1)
+		copy(vs, values)
+		matches = append(matches, match{node: n.wildcard, values: append(vs, path)})
+	}
+	return matches
+}
+
+// expression 约束表达式，无约束时为空
+func (c *constraint) expression() string {
+	if c == nil {
+		return ""
+	}
+	return c.expr
+}
+
+// parsePath 解析 path 中的每一段
+func parsePath(path string) ([]*segment, error) {
+	parts := splitPath(path)
+	segments := make([]*segment, 0, len(parts))
+	names := make(map[string]bool)
+	for i, part := range parts {
+		seg, err := parseSegment(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid route [%v]: %v", path, err)
+		}
+		if seg.kind == kindWildcard && i < len(parts)-1 {
+			return nil, fmt.Errorf("invalid route [%v]: catch-all segment [%v] must be the last one", path, part)
+		}
+		if seg.kind != kindStatic {
+			if names[seg.name] {
+				return nil, fmt.Errorf("invalid route [%v]: duplicate path variable [%v]", path, seg.name)
+			}
+			names[seg.name] = true
+		}
+		if i > 0 && segments[i-1].optional && !seg.optional {
+			return nil, fmt.Errorf("invalid route [%v]: optional segment [%v] must be at the end", path, parts[i-1])
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// hasVariable path 中是否有该参数
+func hasVariable(segments []*segment, name string) bool {
+	for _, seg := range segments {
+		if seg.kind != kindStatic && seg.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSegment 解析单个 path 段
+//
+//	user          -> 静态段
+//	{id}          -> 参数段
+//	{id:int}      -> 类型约束，支持 int、uint、float、bool、uuid
+//	{id:[0-9]+}   -> 正则约束
+//	{id?}         -> 可选参数段，只能出现在末尾
+//	{path...} | * -> 通配段，匹配剩余的全部路径（包括 /）
+func parseSegment(part string) (*segment, error) {
+	if part == wildcardSegment {
+		return &segment{kind: kindWildcard, name: wildcardSegment}, nil
+	}
+	if !strings.HasPrefix(part, "{") || !strings.HasSuffix(part, "}") {
+		if strings.ContainsAny(part, "{}") {
+			return nil, fmt.Errorf("unclosed path variable [%v]", part)
+		}
+		return &segment{kind: kindStatic, value: part}, nil
+	}
+
+	content := part[1 : len(part)-1]
+	name, expr := content, ""
+	if i := strings.IndexByte(content, ':'); i > -1 {
+		name, expr = content[:i], content[i+1:]
+	}
+
+	seg := &segment{kind: kindParam}
+	if strings.HasSuffix(name, catchAllSuffix) {
+		seg.kind = kindWildcard
+		name = strings.TrimSuffix(name, catchAllSuffix)
+		if expr != "" {
+			return nil, fmt.Errorf("catch-all segment [%v] does not support constraint", part)
+		}
+	} else if strings.HasSuffix(name, optionalSuffix) {
+		seg.optional = true
+		name = strings.TrimSuffix(name, optionalSuffix)
+	}
+	if name == "" {
+		return nil, errors.New("path variable name can not be empty")
+	}
+	seg.name = name
+
+	if expr != "" {
+		if match, ok := typedConstraints[expr]; ok {
+			seg.constraint = &constraint{expr: expr, match: match}
+		} else {
+			reg, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint of path variable [%v]: %v", part, err)
+			}
+			seg.constraint = &constraint{expr: expr, match: reg.MatchString}
+		}
+	}
+	return seg, nil
+}
+
+// splitPath 将 path 按 / 分段，自动忽略首尾的 /
+func splitPath(path string) []string {
+	path = trimPath(path)
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// trimPath 去除 path 首尾的 /
+func trimPath(path string) string {
+	if strings.HasPrefix(path, "/") {
+		path = path[1:]
+	}
+	if strings.HasSuffix(path, "/") {
+		path = path[:len(path)-1]
+	}
+	return path
+}
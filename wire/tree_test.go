@@ -0,0 +1,320 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 10:48 上午
+// version: 1.0.0
+// desc   : 路由前缀树测试
+
+package wire
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/yhyzgn/gox/common"
+)
+
+func testHandler() common.Handler {
+	return common.Handler(reflect.ValueOf(func() {}))
+}
+
+// newTestWires 直接建树，避免注册日志干扰基准测试
+func newTestWires(paths ...string) *Wires {
+	w := NewWires()
+	for _, path := range paths {
+		hw := &HandlerWire{Path: path, Handler: testHandler(), Methods: []common.Method{http.MethodGet}}
+		variables, err := w.root.insert(path, hw)
+		if err != nil {
+			panic(err)
+		}
+		hw.variables = variables
+	}
+	return w
+}
+
+func TestWires_Match(t *testing.T) {
+	w := newTestWires(
+		"/",
+		"/api/user",
+		"/api/user/list",
+		"/api/user/{id}",
+		"/api/user/{id}/books/{book}",
+		"/static/*",
+	)
+
+	cases := []struct {
+		path      string
+		matched   string
+		variables map[string]string
+	}{
+		{"/", "/", map[string]string{}},
+		{"/api/user", "/api/user", map[string]string{}},
+		{"/api/user/", "/api/user", map[string]string{}},
+		{"/api/user/list", "/api/user/list", map[string]string{}},
+		{"/api/user/12", "/api/user/{id}", map[string]string{"id": "12"}},
+		{"/api/user/12/books/go", "/api/user/{id}/books/{book}", map[string]string{"id": "12", "book": "go"}},
+		{"/static/css/app.css", "/static/*", map[string]string{"*": "css/app.css"}},
+		{"/api/user/12/books", "", nil},
+		{"/api/unknown", "", nil},
+		{"/static", "", nil},
+	}
+
+	for _, c := range cases {
+		route := w.Match(c.path)
+		if c.matched == "" {
+			if route != nil {
+				t.Errorf("path [%v] should not be matched, but matched [%v]", c.path, route.Wires[0].Path)
+			}
+			continue
+		}
+		if route == nil {
+			t.Errorf("path [%v] should be matched by [%v]", c.path, c.matched)
+			continue
+		}
+		hw := route.Wire(http.MethodGet)
+		if hw.Path != c.matched {
+			t.Errorf("path [%v] should be matched by [%v], but [%v]", c.path, c.matched, hw.Path)
+		}
+		if variables := route.Variables(hw); !reflect.DeepEqual(variables, c.variables) {
+			t.Errorf("path [%v] should have variables %v, but %v", c.path, c.variables, variables)
+		}
+	}
+}
+
+func benchmarkMatch(b *testing.B, count int) {
+	paths := make([]string, 0, count*2)
+	for i := 0; i < count; i++ {
+		paths = append(paths, fmt.Sprintf("/api/module%d/list", i), fmt.Sprintf("/api/module%d/{id}/detail", i))
+	}
+	w := newTestWires(paths...)
+	static := fmt.Sprintf("/api/module%d/list", count-1)
+	param := fmt.Sprintf("/api/module%d/1024/detail", count-1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if w.Match(static) == nil || w.Match(param) == nil {
+			b.Fatal("route not matched")
+		}
+	}
+}
+
+func BenchmarkWires_Match10(b *testing.B) {
+	benchmarkMatch(b, 10)
+}
+
+func BenchmarkWires_Match100(b *testing.B) {
+	benchmarkMatch(b, 100)
+}
+
+func BenchmarkWires_Match1000(b *testing.B) {
+	benchmarkMatch(b, 1000)
+}
+
+func TestWires_MatchConstraint(t *testing.T) {
+	w := newTestWires(
+		"/users/{id:int}",
+		"/users/{name}",
+		"/codes/{code:[a-z]{3}}",
+		"/files/{path...}",
+		"/archive/{year:int}/{month?:int}/{day?:int}",
+	)
+
+	cases := []struct {
+		path      string
+		matched   string
+		variables map[string]string
+	}{
+		{"/users/12", "/users/{id:int}", map[string]string{"id": "12"}},
+		{"/codes/abc", "/codes/{code:[a-z]{3}}", map[string]string{"code": "abc"}},
+		{"/codes/abcd", "", nil},
+		{"/users/jason", "/users/{name}", map[string]string{"name": "jason"}},
+		{"/files/a/b/c.txt", "/files/{path...}", map[string]string{"path": "a/b/c.txt"}},
+		{"/archive/2020", "/archive/{year:int}/{month?:int}/{day?:int}", map[string]string{"year": "2020", "month": "", "day": ""}},
+		{"/archive/2020/05/13", "/archive/{year:int}/{month?:int}/{day?:int}", map[string]string{"year": "2020", "month": "05", "day": "13"}},
+		{"/archive/latest", "", nil},
+		{"/files", "", nil},
+	}
+
+	for _, c := range cases {
+		route := w.Match(c.path)
+		if c.matched == "" {
+			if route != nil {
+				t.Errorf("path [%v] should not be matched, but matched [%v]", c.path, route.Wires[0].Path)
+			}
+			continue
+		}
+		if route == nil {
+			t.Errorf("path [%v] should be matched by [%v]", c.path, c.matched)
+			continue
+		}
+		hw := route.Wire(http.MethodGet)
+		if hw.Path != c.matched {
+			t.Errorf("path [%v] should be matched by [%v], but [%v]", c.path, c.matched, hw.Path)
+		}
+		if variables := route.Variables(hw); !reflect.DeepEqual(variables, c.variables) {
+			t.Errorf("path [%v] should have variables %v, but %v", c.path, c.variables, variables)
+		}
+	}
+}
+
+func TestWires_MatchMethod(t *testing.T) {
+	w := NewWires()
+	for _, r := range []struct {
+		path   string
+		method common.Method
+	}{
+		{"/users/new", http.MethodGet},
+		{"/users/{id}", http.MethodDelete},
+		{"/users/{id:int}", http.MethodPut},
+		{"/files/readme", http.MethodGet},
+		{"/files/*", http.MethodPost},
+	} {
+		hw := &HandlerWire{Path: r.path, Handler: testHandler(), Methods: []common.Method{r.method}}
+		variables, err := w.root.insert(r.path, hw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hw.variables = variables
+	}
+
+	cases := []struct {
+		path      string
+		method    string
+		matched   string
+		variables map[string]string
+	}{
+		// 静态段优先
+		{"/users/new", http.MethodGet, "/users/new", map[string]string{}},
+		// 静态段不支持该方法时，回退到参数段
+		{"/users/new", http.MethodDelete, "/users/{id}", map[string]string{"id": "new"}},
+		{"/users/12", http.MethodPut, "/users/{id:int}", map[string]string{"id": "12"}},
+		{"/users/12", http.MethodDelete, "/users/{id}", map[string]string{"id": "12"}},
+		// 回退到通配段
+		{"/files/readme", http.MethodGet, "/files/readme", map[string]string{}},
+		{"/files/readme", http.MethodPost, "/files/*", map[string]string{"*": "readme"}},
+		{"/users/new", http.MethodPost, "", nil},
+		{"/users/new", http.MethodPut, "", nil},
+	}
+
+	for _, c := range cases {
+		route := w.Match(c.path)
+		if route == nil {
+			t.Errorf("path [%v] should be matched", c.path)
+			continue
+		}
+		hw := route.Wire(c.method)
+		if c.matched == "" {
+			if hw != nil {
+				t.Errorf("[%v %v] should not be matched, but matched [%v]", c.method, c.path, hw.Path)
+			}
+			continue
+		}
+		if hw == nil || hw.Path != c.matched {
+			t.Errorf("[%v %v] should be matched by [%v], but %v", c.method, c.path, c.matched, hw)
+			continue
+		}
+		if variables := route.Variables(hw); !reflect.DeepEqual(variables, c.variables) {
+			t.Errorf("[%v %v] should have variables %v, but %v", c.method, c.path, c.variables, variables)
+		}
+	}
+
+	// Allow 由所有能匹配该 path 的节点共同决定
+	allows := map[string][]common.Method{
+		"/users/new":    {http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions},
+		"/users/12":     {http.MethodDelete, http.MethodPut, http.MethodOptions},
+		"/files/readme": {http.MethodGet, http.MethodHead, http.MethodPost, http.MethodOptions},
+	}
+	for path, expected := range allows {
+		methods := w.Match(path).Methods()
+		if len(methods) != len(expected) {
+			t.Errorf("path [%v] should allow %v, but %v", path, expected, methods)
+			continue
+		}
+		for _, md := range expected {
+			if !contains(methods, md) {
+				t.Errorf("path [%v] should allow %v, but %v", path, expected, methods)
+				break
+			}
+		}
+	}
+}
+
+func contains(methods []common.Method, method common.Method) bool {
+	for _, md := range methods {
+		if md == method {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWires_MappingConflict(t *testing.T) {
+	w := NewWires()
+	get := []common.Method{http.MethodGet}
+	if err := w.Mapping("/users/{id}", testHandler(), get, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Mapping("/users/{id}", testHandler(), []common.Method{http.MethodPost}, nil); err != nil {
+		t.Errorf("different methods should not conflict: %v", err)
+	}
+
+	invalids := []string{
+		"/users/{uid}",
+		"/files/{path...}/raw",
+		"/users/{id?}/detail",
+		"/users/{id:[0-9}",
+		"/users/{id}/{id}",
+		"/users/{id",
+	}
+	for _, path := range invalids {
+		if err := w.Mapping(path, testHandler(), get, nil); err == nil {
+			t.Errorf("the route [%v] should be reported", path)
+		}
+	}
+
+	// 约束不同的参数段，剩余 path 和请求方法相同时无法区分
+	if err := w.Mapping("/orders/{id:int}", testHandler(), get, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Mapping("/orders/{id:int}/items/{item}", testHandler(), get, nil); err != nil {
+		t.Fatal(err)
+	}
+	ambiguous := []string{
+		"/orders/{id:[0-9]+}",
+		"/orders/{no:uuid}/items/{name}",
+	}
+	for _, path := range ambiguous {
+		if err := w.Mapping(path, testHandler(), get, nil); err == nil {
+			t.Errorf("the ambiguous route [%v] should be reported", path)
+		}
+	}
+	if err := w.Mapping("/orders/{id:[0-9]+}", testHandler(), []common.Method{http.MethodPost}, nil); err != nil {
+		t.Errorf("different methods should not be ambiguous: %v", err)
+	}
+	if err := w.Mapping("/orders/{no:uuid}/detail", testHandler(), get, nil); err != nil {
+		t.Errorf("different rest paths should not be ambiguous: %v", err)
+	}
+
+	param := common.NewParam("name", true, false, true, false)
+	param.RealType = reflect.TypeOf("")
+	params := []*common.Param{param}
+	if err := w.Mapping("/books/{id}", testHandler(), get, params); err == nil {
+		t.Error("unknown path variable should be reported")
+	}
+}
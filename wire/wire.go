@@ -21,29 +21,48 @@
 package wire
 
 import (
+	"fmt"
+	"net/http"
 	"reflect"
 	"runtime"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/yhyzgn/gog"
 	"github.com/yhyzgn/gox/common"
+	"github.com/yhyzgn/gox/component/interceptor"
 	"github.com/yhyzgn/gox/util"
 )
 
-// HandlerWire 处理器映射缓存
+// HandlerWire 处理器映射
 type HandlerWire struct {
-	Path    string          // 配置的 path
-	Handler common.Handler  // 处理器
-	Methods []common.Method // 请求方法
-	Params  []*common.Param // 参数列表
+	Path         string                    // 配置的 path
+	Handler      common.Handler            // 处理器
+	Methods      []common.Method           // 请求方法
+	Params       []*common.Param           // 参数列表
+	Interceptors []interceptor.Interceptor // 所属分组的拦截器
+	Produces     []string                  // 可响应的媒体类型，为空时按 Accept 请求头协商
+	Consumes     []string                  // 可接收的请求体媒体类型，为空时不限制
+	MaxBodySize  int64                     // 请求体的最大字节数，不大于 0 时使用全局配置
+	ErrorAdvices []*common.ErrorAdvice     // 所属分组的错误处理器
+	Timeout      time.Duration             // 处理超时时间，不大于 0 时不限制
+	variables    []string                  // path 中按顺序出现的参数名
 }
 
-// Wires 处理器映射缓存
+// Wires 处理器映射集合
 type Wires struct {
+	mu     sync.RWMutex
 	wires  sync.Map       // path 处理器映射
 	sorted []*HandlerWire // 从长到短 排序后的映射
+	root   *node          // 路由前缀树
+}
+
+// Route 路由匹配结果
+type Route struct {
+	Wires   []*HandlerWire // 能匹配该 path 的所有处理器，按路由优先级排列
+	matches []match        // 匹配到的节点及其 path 参数值
 }
 
 var (
@@ -54,30 +73,87 @@ var (
 
 func init() {
 	once.Do(func() {
-		Instance = &Wires{
-			sorted: make([]*HandlerWire, 0),
-		}
+		Instance = NewWires()
 	})
 }
 
-// Mapping 注册映射关系
-func (w *Wires) Mapping(path string, handler common.Handler, methods []common.Method, params []*common.Param) {
-	wire := &HandlerWire{
-		Path:    path,
-		Handler: handler,
-		Methods: methods,
-		Params:  params,
+// NewWires 创建新的映射集合
+func NewWires() *Wires {
+	return &Wires{
+		sorted: make([]*HandlerWire, 0),
+		root:   newNode(kindStatic),
+	}
+}
+
+// Mapping 注册一条映射关系
+//
+// path 非法、与已注册的路由冲突，或者注册的 path 参数不在 path 中时返回错误
+func (w *Wires) Mapping(path string, handler common.Handler, methods []common.Method, params []*common.Param, interceptors ...interceptor.Interceptor) error {
+	return w.Add(&HandlerWire{
+		Path:         path,
+		Handler:      handler,
+		Methods:      methods,
+		Params:       params,
+		Interceptors: interceptors,
+	})
+}
+
+// Add 注册一个已配置好的处理器映射
+//
+// 同 Mapping，用于配置 Produces 等更多路由属性
+func (w *Wires) Add(wire *HandlerWire) error {
+	path, params, methods := wire.Path, wire.Params, wire.Methods
+
+	pc := reflect.Value(wire.Handler).Pointer()
+	name := strings.ReplaceAll(runtime.FuncForPC(pc).Name(), "-fm", util.FormatHandlerArgs(wire.Params))
+
+	// 检查注册的 path 参数
+	segments, err := parsePath(path)
+	if err != nil {
+		return fmt.Errorf("mapping handler [%v] failed: %v", name, err)
+	}
+	for _, param := range params {
+		if param != nil && param.InPath && !hasVariable(segments, param.Name) {
+			return fmt.Errorf("the path [%v] of handler [%v] does not contains path variable [%v]", path, name, param.Name)
+		}
 	}
+
+	w.mu.Lock()
+	// 注册时即建好路由树，请求时无需再逐个匹配
+	variables, err := w.root.insert(path, wire)
+	if err != nil {
+		w.mu.Unlock()
+		return fmt.Errorf("mapping handler [%v] failed: %v", name, err)
+	}
+	wire.variables = variables
 	// Request 节点  或者  路径长度 从长到端排序
 	w.sorted = appendSorted(w.sorted, wire)
+	w.mu.Unlock()
 	w.wires.Store(path, wire)
 
-	pc := reflect.Value(handler).Pointer()
-	name := strings.ReplaceAll(runtime.FuncForPC(pc).Name(), "-fm", util.FormatHandlerArgs(wire.Params))
 	gog.InfoF("Mapped [%v-->\t%v] with http methods %v", util.FillSuffix(path, " ", 40), name, methods)
+	return nil
+}
+
+// Match 按请求路径匹配路由
+//
+// 匹配耗时只与 path 段数有关，与注册的路由数量无关
+func (w *Wires) Match(path string) *Route {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	matches := w.root.lookup(trimPath(path), nil, nil)
+	if len(matches) == 0 {
+		return nil
+	}
+	route := &Route{matches: matches}
+	for _, m := range matches {
+		route.Wires = append(route.Wires, m.node.wires...)
+	}
+	return route
 }
 
-// Get 获取一条映射关系
+// Get 按注册的 path 获取映射
 func (w *Wires) Get(path string) *HandlerWire {
 	wire, ok := w.wires.Load(path)
 	if !ok {
@@ -86,11 +162,82 @@ func (w *Wires) Get(path string) *HandlerWire {
 	return wire.(*HandlerWire)
 }
 
-// All 获取所有映射关系
+// All 获取所有映射
 func (w *Wires) All() []*HandlerWire {
 	return w.sorted
 }
 
+// Wire 获取支持该请求方法的处理器
+//
+// 按路由优先级查找，优先级高的节点不支持该方法时回退到下一个节点，都不支持时返回 nil
+func (r *Route) Wire(method string) *HandlerWire {
+	for _, hw := range r.Wires {
+		if hw.Supports(method) {
+			return hw
+		}
+	}
+	return nil
+}
+
+// Methods 获取能匹配该 path 的所有处理器支持的请求方法
+//
+// 支持 GET 时自动支持 HEAD，并且总是支持 OPTIONS
+func (r *Route) Methods() []common.Method {
+	set := common.NewMethodSet()
+	for _, hw := range r.Wires {
+		for _, md := range hw.Methods {
+			set.Add(md)
+			if md == http.MethodGet {
+				set.Add(http.MethodHead)
+			}
+		}
+	}
+	return set.Add(http.MethodOptions).Methods()
+}
+
+// Variables 获取处理器对应的 path 参数
+//
+// 未匹配到的可选参数值为空字符串
+func (r *Route) Variables(hw *HandlerWire) map[string]string {
+	var values []string
+	for _, m := range r.matches {
+		if m.node.has(hw) {
+			values = m.values
+			break
+		}
+	}
+
+	variables := make(map[string]string, len(hw.variables))
+	for i, name := range hw.variables {
+		if i < len(values) {
+			variables[name] = values[i]
+		} else {
+			variables[name] = ""
+		}
+	}
+	return variables
+}
+
+// Supports 是否支持该请求方法
+func (hw *HandlerWire) Supports(method string) bool {
+	for _, md := range hw.Methods {
+		if string(md) == method {
+			return true
+		}
+	}
+	return false
+}
+
+// overlaps 获取两个处理器共同支持的第一个请求方法，没有则为空
+func (hw *HandlerWire) overlaps(other *HandlerWire) common.Method {
+	for _, md := range other.Methods {
+		if hw.Supports(string(md)) {
+			return md
+		}
+	}
+	return ""
+}
+
 // appendSorted 按 path 从长到短 插入数组
 func appendSorted(wires []*HandlerWire, wire *HandlerWire) []*HandlerWire {
 	length := len(wires)
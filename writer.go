@@ -0,0 +1,115 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 10:40 下午
+// version: 1.0.0
+// desc   : 记录响应状态码的 ResponseWriter
+//			响应的状态码配置了错误码处理器时，丢弃原来的响应，改由处理器响应
+
+package gox
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+)
+
+// statusWriter 记录响应状态码
+type statusWriter struct {
+	http.ResponseWriter
+	lookup  func(statusCode int) http.HandlerFunc // 查找错误码处理器
+	status  int                                   // 已响应的状态码
+	handler http.HandlerFunc                      // 匹配到的错误码处理器
+}
+
+// newStatusWriter 创建记录响应状态码的 ResponseWriter
+func newStatusWriter(writer http.ResponseWriter, lookup func(statusCode int) http.HandlerFunc) *statusWriter {
+	return &statusWriter{
+		ResponseWriter: writer,
+		lookup:         lookup,
+	}
+}
+
+// WriteHeader 记录状态码，配置了错误码处理器时不再响应
+func (sw *statusWriter) WriteHeader(statusCode int) {
+	if sw.status != 0 {
+		return
+	}
+	sw.status = statusCode
+	if handler := sw.lookup(statusCode); handler != nil {
+		sw.handler = handler
+		return
+	}
+	sw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write 写入响应体，配置了错误码处理器时直接丢弃
+func (sw *statusWriter) Write(bs []byte) (int, error) {
+	if sw.status == 0 {
+		sw.WriteHeader(http.StatusOK)
+	}
+	if sw.handler != nil {
+		return len(bs), nil
+	}
+	return sw.ResponseWriter.Write(bs)
+}
+
+// Flush 支持流式响应
+func (sw *statusWriter) Flush() {
+	if sw.handler != nil {
+		return
+	}
+	if flusher, ok := sw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack 支持 websocket 等接管连接的场景，接管后不再由错误码处理器响应
+func (sw *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := sw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, http.ErrNotSupported
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err == nil {
+		sw.handler = nil
+	}
+	return conn, rw, err
+}
+
+// Push 支持 HTTP/2 服务端推送
+func (sw *statusWriter) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := sw.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// Unwrap 获取原始的 ResponseWriter
+func (sw *statusWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
+
+// finish 请求处理完成后，交给错误码处理器响应
+func (sw *statusWriter) finish(request *http.Request) {
+	if sw.handler == nil {
+		return
+	}
+	// 原来的响应已丢弃，其内容相关的响应头不再有效
+	sw.ResponseWriter.Header().Del("Content-Type")
+	sw.ResponseWriter.Header().Del("Content-Length")
+	sw.handler(sw.ResponseWriter, request)
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 yhyzgn gox
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author : 颜洪毅
+// e-mail : yhyzgn@gmail.com
+// time   : 2026-10-18 11:50 上午
+// version: 1.0.0
+// desc   : 响应器测试
+
+package gox
+
+import (
+	"github.com/yhyzgn/gox/core"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type hijackController struct {
+}
+
+func (w hijackController) Mapping(mapper *core.Mapper) {
+	mapper.Get("/hijack").HandlerFunc(w.Hijack).Mapping()
+}
+
+func (hijackController) Hijack(writer http.ResponseWriter) {
+	hijacker, ok := writer.(http.Hijacker)
+	if !ok {
+		writer.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+	_, _ = rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+	_ = rw.Flush()
+}
+
+func TestGoX_Hijack(t *testing.T) {
+	gx := NewGoX().Mapping("/api", hijackController{}).ErrorCodeHandler(http.StatusNotFound, func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNotFound)
+	})
+	if _, ok := interface{}(newStatusWriter(httptest.NewRecorder(), gx.GetErrorHandler)).(http.Hijacker); !ok {
+		t.Fatal("status writer should implement http.Hijacker")
+	}
+
+	server := httptest.NewServer(gx)
+	defer server.Close()
+	response, err := http.Get(server.URL + "/api/hijack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	bs, _ := ioutil.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK || string(bs) != "hijacked" {
+		t.Errorf("connection should be hijacked, but %d %q", response.StatusCode, bs)
+	}
+}